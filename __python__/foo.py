@@ -1,5 +1,6 @@
 # foo.py
 import random
+import sys
 import pandas as pd
 import pyarrow as pa
 
@@ -26,3 +27,242 @@ def zero_copy_elements(num_elements=5):
     batches = [batch]
     table = pa.Table.from_batches(batches)
     return table
+
+
+def record_batch_iter(num_batches=5):
+    table = zero_copy_chunks(num_batches)
+    return iter(table.to_batches())
+
+
+def record_batch_stream(num_batches=5):
+    table = zero_copy_chunks(num_batches)
+    sink = pa.BufferOutputStream()
+    writer = pa.RecordBatchStreamWriter(sink, table.schema)
+    writer.write_table(table)
+    writer.close()
+    return pa.ipc.open_stream(sink.getvalue())
+
+
+def nullable_elements(num_elements=5):
+    a1 = pa.array([random.uniform(1000, 2000) for x in range(num_elements)] + [None])
+    a2 = pa.array(['foo'] * num_elements + [None])
+    data = [a1, a2]
+    batch = pa.RecordBatch.from_arrays(data, ['f0', 'f1'])
+    return pa.Table.from_batches([batch])
+
+
+def total_allocated_bytes():
+    return pa.total_allocated_bytes()
+
+
+def nested_types():
+    lists = pa.array([[1, 2], None, [3], []], type=pa.list_(pa.int64()))
+    structs = pa.array(
+        [{'a': 1, 'b': 'x'}, None, {'a': 3, 'b': None}, {'a': None, 'b': 'z'}],
+        type=pa.struct([('a', pa.int64()), ('b', pa.string())]))
+    arrays, names = [lists, structs], ['lists', 'structs']
+    # Older versions of pyarrow have neither fixed size lists nor maps.
+    if hasattr(pa, 'FixedSizeListArray'):
+        arrays.append(pa.array([[1, 2], None, [3, 4], [5, 6]], type=pa.list_(pa.int64(), 2)))
+        names.append('fixed_size_lists')
+    if hasattr(pa, 'map_'):
+        arrays.append(pa.array(
+            [[('a', 1)], None, [('b', 2), ('c', None)], []],
+            type=pa.map_(pa.string(), pa.int64())))
+        names.append('maps')
+    batch = pa.RecordBatch.from_arrays(arrays, names)
+    # The sliced batch makes sure the offsets of the chunks are respected.
+    return pa.Table.from_batches([batch, batch.slice(1, 3)])
+
+
+def temporal_types():
+    arrays = [
+        pa.array([0, 1, None], type=pa.timestamp('ns', tz='UTC')),
+        pa.array([0, 1, None], type=pa.timestamp('ms')),
+        pa.array([0, 1, None], type=pa.time32('s')),
+        pa.array([0, 1, None], type=pa.time64('us')),
+    ]
+    names = ['timestamp_ns_utc', 'timestamp_ms', 'time32_s', 'time64_us']
+    # pyarrow < 0.14 has no duration type.
+    if hasattr(pa, 'duration'):
+        arrays.append(pa.array([0, 1, None], type=pa.duration('ns')))
+        names.append('duration_ns')
+    return pa.Table.from_arrays(arrays, names)
+
+
+def decimal_and_fixed_size_binary():
+    from decimal import Decimal
+    decimals = pa.array([Decimal('1.23'), None, Decimal('-4.56')], type=pa.decimal128(5, 2))
+    uuids = pa.array([b'0123456789abcdef', None, b'fedcba9876543210'], type=pa.binary(16))
+    return pa.Table.from_arrays([decimals, uuids], ['decimals', 'uuids'])
+
+
+def chunk_dictionaries():
+    # pyarrow < 0.14 keeps the dictionary in the type rather than in every
+    # chunk, the bridge only supports the latter.
+    return hasattr(pa.DictionaryType, 'value_type')
+
+
+def dictionary_chunks():
+    if not chunk_dictionaries():
+        return None
+    first = pa.DictionaryArray.from_arrays(
+        pa.array([0, 1, None, 0], type=pa.int8()), pa.array(['a', 'b']))
+    second = pa.DictionaryArray.from_arrays(
+        pa.array([1, 0], type=pa.int8()), pa.array(['c', 'a']))
+    return pa.Table.from_arrays([pa.chunked_array([first, second])], ['categories'])
+
+
+def nested_dictionary():
+    if not chunk_dictionaries():
+        return None
+    dictionary = pa.DictionaryArray.from_arrays(
+        pa.array([0, 1, 0], type=pa.int8()), pa.array(['a', 'b']))
+    lists = pa.ListArray.from_arrays(pa.array([0, 2, 3], type=pa.int32()), dictionary)
+    structs = pa.StructArray.from_arrays([dictionary], ['d'])
+    arrays = [pa.array([1, 2, 3]), lists, structs]
+    return pa.Table.from_arrays(arrays, ['ok', 'lists', 'structs'])
+
+
+def primitive_types():
+    types = [
+        pa.null(), pa.bool_(),
+        pa.uint8(), pa.int8(), pa.uint16(), pa.int16(),
+        pa.uint32(), pa.int32(), pa.uint64(), pa.int64(),
+        pa.float32(), pa.float64(),
+        pa.string(), pa.binary(), pa.date32(), pa.date64(),
+    ]
+    arrays = [pa.array([None], type=t) for t in types]
+    return pa.Table.from_arrays(arrays, [str(t) for t in types])
+
+
+def unsupported_type():
+    if not hasattr(pa, 'large_string'):
+        return None
+    arrays = [pa.array([1]), pa.array(['a'], type=pa.large_string())]
+    return pa.Table.from_arrays(arrays, ['ok', 'large'])
+
+
+def decimal256_type():
+    if not hasattr(pa, 'decimal256'):
+        return None
+    from decimal import Decimal
+    decimals = pa.array([Decimal('1.23'), None], type=pa.decimal256(40, 2))
+    return pa.Table.from_arrays([decimals], ['d'])
+
+
+def pandas_table(n):
+    # The pandas metadata records the range of the index, it differs with n.
+    return pa.Table.from_pandas(pd.DataFrame({'a': list(range(n))}))
+
+
+def metadata_table():
+    df = pd.DataFrame({'a': [1, 2, 3]})
+    pandas_metadata = pa.Table.from_pandas(df, preserve_index=False).schema.metadata
+    schema_metadata = dict(pandas_metadata)
+    schema_metadata[b'origin'] = b'test'
+    fields = [pa.field('a', pa.int64(), metadata={b'lineage': b'source-a'})]
+    schema = pa.schema(fields, metadata=schema_metadata)
+    return pa.Table.from_arrays([pa.array([1, 2, 3])], schema=schema)
+
+
+def non_null_columns():
+    schema = pa.schema([
+        pa.field('ints', pa.int64(), nullable=False),
+        pa.field('strings', pa.string(), nullable=False),
+        pa.field('floats', pa.float64()),
+    ])
+    arrays = [
+        pa.array([1, 2, 3], type=pa.int64()),
+        pa.array(['a', 'b', 'c']),
+        pa.array([1.5, 2.5, 3.5]),
+    ]
+    return pa.Table.from_arrays(arrays, schema=schema)
+
+
+def single_array():
+    # The slice makes sure the offset of the array is respected.
+    return pa.array([0, 1, None, 3, 4], type=pa.int32()).slice(1, 3)
+
+
+def single_chunked_array():
+    return pa.chunked_array([
+        pa.array(['a', None]),
+        pa.array(['b', 'c', 'd']).slice(1),
+    ])
+
+
+def record_batch():
+    return pa.RecordBatch.from_arrays(
+        [pa.array([1, None, 3]), pa.array(['a', 'b', None])],
+        ['ints', 'strings'])
+
+
+class BrokenChunked(object):
+    """Looks like a ChunkedArray but its last chunk is not an array."""
+
+    @property
+    def chunks(self):
+        # Like pyarrow, return a new list every time.
+        return [pa.array([1, 2, 3]), pa.array([4, None]), 'not an array']
+
+
+def broken_chunked():
+    return BrokenChunked()
+
+
+def refcount(obj):
+    return sys.getrefcount(obj)
+
+
+class PersistentChunked(object):
+    """Looks like a ChunkedArray but always returns the same chunks list, so
+    that over-released chunks show up in their reference counts."""
+
+    def __init__(self):
+        self.chunks = [pa.array([1, None, 3]), pa.array([4, 5])]
+
+
+def persistent_chunked():
+    return PersistentChunked()
+
+
+def sliced_types():
+    arrays = [
+        pa.array([True, None, False, True, None, True, False, True, True, None]),
+        pa.array(['a', None, 'bc', '', 'def', None, 'g', 'hi', 'j', 'k']),
+        pa.array([[1], None, [2, 3], [], [4, 5, 6], [7], None, [8], [9], [10]]),
+        pa.array([{'a': i, 'b': str(i)} if i % 3 else None for i in range(10)]),
+        pa.array(list(range(10)), type=pa.int16()),
+    ]
+    names = ['bools', 'strings', 'lists', 'structs', 'ints']
+    if chunk_dictionaries():
+        arrays.append(pa.array(['x', 'y', 'x', None, 'y', 'z', 'x', 'y', 'z', 'x']).dictionary_encode())
+        names.append('dict')
+    return pa.Table.from_arrays([a.slice(3, 5) for a in arrays], names)
+
+
+def large_slice():
+    return pa.Table.from_arrays([pa.array(range(100000)).slice(500, 3)], ['ints'])
+
+
+def identity(obj):
+    return obj
+
+
+def column_names(table):
+    return pa.array(table.schema.names)
+
+
+def duplicate_names():
+    arrays = [pa.array([1, 2]), pa.array(['a', 'b']), pa.array([1.5, 2.5]), pa.array([True, False])]
+    return pa.Table.from_arrays(arrays, ['x', 'x', '', ''])
+
+
+def duplicate_names_batch():
+    return duplicate_names().to_batches()[0]
+
+
+def duplicate_struct_fields():
+    structs = pa.StructArray.from_arrays([pa.array([1, 2]), pa.array(['a', 'b'])], ['a', 'a'])
+    return pa.Table.from_arrays([structs], ['s'])
@@ -0,0 +1,228 @@
+#include "bridge.h"
+#include <stdlib.h>
+
+#include "_cgo_export.h"
+
+#define BRIDGE_CAPSULE_NAME "go_py_arrow_bridge.buffer"
+
+static void bridge_capsule_destructor(PyObject *capsule) {
+	void *handle = PyCapsule_GetPointer(capsule, BRIDGE_CAPSULE_NAME);
+	goReleaseBuffer((uintptr_t)handle);
+}
+
+// bridge_new_capsule returns a capsule that releases the Go buffer
+// registered under handle once Python no longer references it.
+PyObject *bridge_new_capsule(uintptr_t handle) {
+	return PyCapsule_New((void *)handle, BRIDGE_CAPSULE_NAME, bridge_capsule_destructor);
+}
+
+// bridge_intern returns a new reference to the interned string s.
+PyObject *bridge_intern(const char *s) {
+	return PyUnicode_InternFromString(s);
+}
+
+// bridge_interpreter identifies the interpreter of the calling thread, which
+// must hold the GIL.
+void *bridge_interpreter(void) {
+	return PyThreadState_Get()->interp;
+}
+
+static void bridge_finalize(void) {
+	goFinalizePyCache();
+}
+
+// bridge_register_finalize makes Py_Finalize drop the cache of the
+// interpreter. Py_Finalize forgets the functions it called, so it is
+// registered again for every new interpreter. It returns -1 on failure.
+int bridge_register_finalize(void) {
+	return Py_AtExit(bridge_finalize);
+}
+
+// bridge_get_buffer exports obj into a newly allocated Py_buffer. The view
+// holds a reference to obj until it is passed to bridge_release_buffer.
+// It returns NULL, with a Python exception set, on failure.
+Py_buffer *bridge_get_buffer(PyObject *obj) {
+	Py_buffer *view = (Py_buffer *)calloc(1, sizeof(Py_buffer));
+	if (view == NULL) {
+		PyErr_NoMemory();
+		return NULL;
+	}
+	if (PyObject_GetBuffer(obj, view, PyBUF_SIMPLE) != 0) {
+		free(view);
+		return NULL;
+	}
+	return view;
+}
+
+// bridge_release_buffer releases the view and the reference it holds to the
+// exporting object. The GIL must be held.
+void bridge_release_buffer(Py_buffer *view) {
+	PyBuffer_Release(view);
+	free(view);
+}
+
+static int bridge_get_int64_attr(PyObject *obj, const char *name, int64_t *out) {
+	PyObject *value = PyObject_GetAttrString(obj, name);
+	if (value == NULL) {
+		return -1;
+	}
+	*out = PyLong_AsLongLong(value);
+	Py_DECREF(value);
+	if (*out == -1 && PyErr_Occurred()) {
+		return -1;
+	}
+	return 0;
+}
+
+// bridge_get_chunk fills in the chunk i of chunks from the pyarrow Array.
+static int bridge_get_chunk(PyObject *chunk, struct bridge_chunks *chunks, Py_ssize_t i) {
+	Py_ssize_t length = PyObject_Length(chunk);
+	if (length < 0) {
+		return -1;
+	}
+	chunks->lengths[i] = length;
+
+	if (bridge_get_int64_attr(chunk, "null_count", &chunks->null_counts[i]) != 0 ||
+	    bridge_get_int64_attr(chunk, "offset", &chunks->offsets[i]) != 0) {
+		return -1;
+	}
+
+	PyObject *buffers = PyObject_CallMethod(chunk, "buffers", NULL);
+	if (buffers == NULL) {
+		return -1;
+	}
+	if (!PyList_Check(buffers)) {
+		Py_DECREF(buffers);
+		PyErr_SetString(PyExc_TypeError, "buffers() did not return a list");
+		return -1;
+	}
+
+	Py_ssize_t n = PyList_GET_SIZE(buffers);
+	if (n > chunks->n_buffers) {
+		n = chunks->n_buffers;
+	}
+	for (Py_ssize_t j = 0; j < n; j++) {
+		PyObject *buffer = PyList_GET_ITEM(buffers, j);
+		if (buffer == Py_None) {
+			continue;
+		}
+		Py_buffer *view = bridge_get_buffer(buffer);
+		if (view == NULL) {
+			Py_DECREF(buffers);
+			return -1;
+		}
+		chunks->views[i * chunks->n_buffers + j] = view;
+	}
+	Py_DECREF(buffers);
+	return 0;
+}
+
+// bridge_get_chunks describes all the chunks of a pyarrow ChunkedArray in
+// one call, taking a view of the first n_buffers buffers of every chunk.
+// The caller owns the views and frees the rest with bridge_free_chunks.
+// It returns NULL, with a Python exception set, on failure; failed_chunk is
+// then set to the index of the failing chunk, or -1.
+struct bridge_chunks *bridge_get_chunks(PyObject *chunked, int64_t n_buffers, int64_t *failed_chunk) {
+	*failed_chunk = -1;
+
+	PyObject *list = PyObject_GetAttrString(chunked, "chunks");
+	if (list == NULL) {
+		return NULL;
+	}
+	PyObject *seq = PySequence_Fast(list, "chunks is not a sequence");
+	Py_DECREF(list);
+	if (seq == NULL) {
+		return NULL;
+	}
+
+	Py_ssize_t n = PySequence_Fast_GET_SIZE(seq);
+	// calloc may return NULL for 0 bytes.
+	size_t m = n > 0 ? (size_t)n : 1;
+	struct bridge_chunks *chunks = (struct bridge_chunks *)calloc(1, sizeof(struct bridge_chunks));
+	if (chunks != NULL) {
+		chunks->n_chunks = n;
+		chunks->n_buffers = n_buffers;
+		chunks->lengths = (int64_t *)calloc(m, sizeof(int64_t));
+		chunks->null_counts = (int64_t *)calloc(m, sizeof(int64_t));
+		chunks->offsets = (int64_t *)calloc(m, sizeof(int64_t));
+		chunks->views = (Py_buffer **)calloc(m * (n_buffers > 0 ? n_buffers : 1), sizeof(Py_buffer *));
+	}
+	if (chunks == NULL || chunks->lengths == NULL || chunks->null_counts == NULL ||
+	    chunks->offsets == NULL || chunks->views == NULL) {
+		bridge_release_chunks(chunks);
+		Py_DECREF(seq);
+		PyErr_NoMemory();
+		return NULL;
+	}
+
+	for (Py_ssize_t i = 0; i < n; i++) {
+		if (bridge_get_chunk(PySequence_Fast_GET_ITEM(seq, i), chunks, i) != 0) {
+			*failed_chunk = i;
+			bridge_release_chunks(chunks);
+			Py_DECREF(seq);
+			return NULL;
+		}
+	}
+
+	Py_DECREF(seq);
+	return chunks;
+}
+
+// bridge_free_chunks frees chunks, but not the views, which belong to the
+// caller.
+void bridge_free_chunks(struct bridge_chunks *chunks) {
+	if (chunks == NULL) {
+		return;
+	}
+	free(chunks->lengths);
+	free(chunks->null_counts);
+	free(chunks->offsets);
+	free(chunks->views);
+	free(chunks);
+}
+
+// bridge_release_chunks releases the views of chunks and frees it. The GIL
+// must be held.
+void bridge_release_chunks(struct bridge_chunks *chunks) {
+	if (chunks == NULL) {
+		return;
+	}
+	if (chunks->views != NULL) {
+		for (int64_t i = 0; i < chunks->n_chunks * chunks->n_buffers; i++) {
+			if (chunks->views[i] != NULL) {
+				bridge_release_buffer(chunks->views[i]);
+			}
+		}
+	}
+	bridge_free_chunks(chunks);
+}
+
+// bridge_new_arrow_schema allocates a zeroed ArrowSchema for a producer to
+// export into.
+struct ArrowSchema *bridge_new_arrow_schema(void) {
+	return (struct ArrowSchema *)calloc(1, sizeof(struct ArrowSchema));
+}
+
+// bridge_free_arrow_schema releases the exported schema, if any, and frees
+// the struct itself.
+void bridge_free_arrow_schema(struct ArrowSchema *schema) {
+	if (schema->release != NULL) {
+		schema->release(schema);
+	}
+	free(schema);
+}
+
+// bridge_new_arrow_array allocates a zeroed ArrowArray for a producer to
+// export into.
+struct ArrowArray *bridge_new_arrow_array(void) {
+	return (struct ArrowArray *)calloc(1, sizeof(struct ArrowArray));
+}
+
+// bridge_free_arrow_array releases the exported array, if any, and frees
+// the struct itself.
+void bridge_free_arrow_array(struct ArrowArray *array) {
+	if (array->release != NULL) {
+		array->release(array);
+	}
+	free(array);
+}
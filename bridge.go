@@ -0,0 +1,194 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+// Bridge converts between pyarrow and Go arrow on a pytasks.PythonSingleton.
+// Unlike the package level functions, its methods acquire the GIL
+// themselves and can be called from any goroutine.
+type Bridge struct {
+	py   pytasks.PythonSingleton
+	conv *Converter
+}
+
+// NewBridge returns a Bridge running on py that converts with the options
+// opts, see NewConverter.
+func NewBridge(py pytasks.PythonSingleton, opts ...ConverterOption) (*Bridge, error) {
+	conv, err := NewConverter(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Intern the attribute names and look up the pyarrow functions now
+	// rather than during the first conversion.
+	if err := py.NewTaskSync(func() { getPyCache() }); err != nil {
+		return nil, err
+	}
+	return &Bridge{py: py, conv: conv}, nil
+}
+
+// Do calls fn while holding the GIL and returns its error. It is the
+// escape hatch for anything the other methods do not cover.
+func (b *Bridge) Do(fn func() error) error {
+	var err error
+	taskErr := b.py.NewTaskSync(func() {
+		err = fn()
+	})
+	if taskErr != nil {
+		return taskErr
+	}
+	return err
+}
+
+// ImportTable calls the function funcName of the Python module with args
+// and converts the pyarrow Table it returns. See CallModuleFunc for the
+// supported args. The returned table must be Release()'d after use.
+func (b *Bridge) ImportTable(module, funcName string, args ...interface{}) (array.Table, error) {
+	var table array.Table
+	err := b.doCall(module, funcName, args, func(pyTable *python3.PyObject) (err error) {
+		table, err = b.conv.PyTableToTable(pyTable)
+		return err
+	})
+	return table, err
+}
+
+// ImportLazyTable is ImportTable returning a LazyTable, which converts the
+// columns when they are first requested.
+func (b *Bridge) ImportLazyTable(module, funcName string, args ...interface{}) (*LazyTable, error) {
+	var table *LazyTable
+	err := b.doCall(module, funcName, args, func(pyTable *python3.PyObject) (err error) {
+		table, err = b.conv.NewLazyTable(pyTable)
+		return err
+	})
+	return table, err
+}
+
+// ImportRecordBatch is ImportTable for a function returning a pyarrow
+// RecordBatch.
+func (b *Bridge) ImportRecordBatch(module, funcName string, args ...interface{}) (array.Record, error) {
+	var rec array.Record
+	err := b.doCall(module, funcName, args, func(pyBatch *python3.PyObject) (err error) {
+		rec, err = b.conv.PyRecordBatchToRecord(pyBatch)
+		return err
+	})
+	return rec, err
+}
+
+// ImportArray is ImportTable for a function returning a pyarrow Array.
+func (b *Bridge) ImportArray(module, funcName string, args ...interface{}) (array.Interface, error) {
+	var arr array.Interface
+	err := b.doCall(module, funcName, args, func(pyArray *python3.PyObject) (err error) {
+		arr, err = b.conv.PyArrayToArray(pyArray)
+		return err
+	})
+	return arr, err
+}
+
+// ImportRecordReader is ImportTable for a function returning a pyarrow
+// RecordBatchReader or an iterator of RecordBatches.
+func (b *Bridge) ImportRecordReader(module, funcName string, args ...interface{}) (*PyRecordReader, error) {
+	var r *PyRecordReader
+	err := b.doCall(module, funcName, args, func(pyReader *python3.PyObject) (err error) {
+		r, err = b.conv.NewPyRecordReader(pyReader)
+		return err
+	})
+	return r, err
+}
+
+// doCall calls the function of the module with args and fn with its result
+// while holding the GIL.
+func (b *Bridge) doCall(module, funcName string, args []interface{}, fn func(*python3.PyObject) error) error {
+	return b.Do(func() error {
+		pyResult, err := callModuleFunc(module, funcName, args...)
+		if err != nil {
+			return err
+		}
+		defer pyResult.DecRef()
+
+		return fn(pyResult)
+	})
+}
+
+// CallModuleFunc imports the Python module and calls its function funcName
+// with args. The args can be nil, bool, int, int64, float64, string,
+// []byte, *python3.PyObject, array.Table or array.Record; tables and
+// records are passed as pyarrow objects sharing the Go buffers.
+// It must be called with the GIL held.
+func CallModuleFunc(module, funcName string, args ...interface{}) (*python3.PyObject, error) {
+	checkGIL()
+
+	return callModuleFunc(module, funcName, args...)
+}
+
+func callModuleFunc(module, funcName string, args ...interface{}) (*python3.PyObject, error) {
+	pyModule := python3.PyImport_ImportModule(module)
+	if pyModule == nil {
+		return nil, pyErrorf("could not import %s", module)
+	}
+	defer pyModule.DecRef()
+
+	pyArgs := make([]*python3.PyObject, 0, len(args))
+	defer func() {
+		for i := range pyArgs {
+			pyArgs[i].DecRef()
+		}
+	}()
+
+	for i, arg := range args {
+		pyArg, err := goValueToPyObject(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %v", i, err)
+		}
+		pyArgs = append(pyArgs, pyArg)
+	}
+
+	pyResult := CallPyFunc(pyModule, funcName, pyArgs...)
+	if pyResult == nil {
+		return nil, pyErrorf("could not call %s.%s", module, funcName)
+	}
+	return pyResult, nil
+}
+
+// goValueToPyObject returns a new reference to the Python equivalent of v.
+func goValueToPyObject(v interface{}) (*python3.PyObject, error) {
+	var pyObj *python3.PyObject
+	switch v := v.(type) {
+	case nil:
+		python3.Py_None.IncRef()
+		return python3.Py_None, nil
+	case bool:
+		if v {
+			pyObj = python3.PyBool_FromLong(1)
+		} else {
+			pyObj = python3.PyBool_FromLong(0)
+		}
+	case int:
+		pyObj = python3.PyLong_FromLong(v)
+	case int64:
+		pyObj = python3.PyLong_FromLongLong(v)
+	case float64:
+		pyObj = python3.PyFloat_FromDouble(v)
+	case string:
+		pyObj = python3.PyUnicode_FromString(v)
+	case []byte:
+		pyObj = newPyBytes(string(v))
+	case *python3.PyObject:
+		v.IncRef()
+		return v, nil
+	case array.Table:
+		return tableToPyTable(v)
+	case array.Record:
+		return recordToPyRecordBatch(v)
+	default:
+		return nil, fmt.Errorf("can not pass a %T to Python", v)
+	}
+	if pyObj == nil {
+		return nil, pyErrorf("could not convert a %T to Python", v)
+	}
+	return pyObj, nil
+}
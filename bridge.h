@@ -0,0 +1,81 @@
+#ifndef GO_PY_ARROW_BRIDGE_H
+#define GO_PY_ARROW_BRIDGE_H
+
+#include "Python.h"
+#include <stdint.h>
+
+PyObject *bridge_new_capsule(uintptr_t handle);
+
+PyObject *bridge_intern(const char *s);
+void *bridge_interpreter(void);
+int bridge_register_finalize(void);
+
+Py_buffer *bridge_get_buffer(PyObject *obj);
+void bridge_release_buffer(Py_buffer *view);
+
+// The chunks of a ChunkedArray, as described by bridge_get_chunks.
+struct bridge_chunks {
+	int64_t n_chunks;
+	int64_t n_buffers; // per chunk
+	int64_t *lengths;
+	int64_t *null_counts;
+	int64_t *offsets;
+	// n_chunks * n_buffers views, NULL where the buffer is None.
+	Py_buffer **views;
+};
+
+struct bridge_chunks *bridge_get_chunks(PyObject *chunked, int64_t n_buffers, int64_t *failed_chunk);
+void bridge_free_chunks(struct bridge_chunks *chunks);
+void bridge_release_chunks(struct bridge_chunks *chunks);
+
+// Arrow C data interface
+// https://arrow.apache.org/docs/format/CDataInterface.html
+
+#ifndef ARROW_C_DATA_INTERFACE
+#define ARROW_C_DATA_INTERFACE
+
+#define ARROW_FLAG_DICTIONARY_ORDERED 1
+#define ARROW_FLAG_NULLABLE 2
+#define ARROW_FLAG_MAP_KEYS_SORTED 4
+
+struct ArrowSchema {
+	// Array type description
+	const char *format;
+	const char *name;
+	const char *metadata;
+	int64_t flags;
+	int64_t n_children;
+	struct ArrowSchema **children;
+	struct ArrowSchema *dictionary;
+
+	// Release callback
+	void (*release)(struct ArrowSchema *);
+	// Opaque producer-specific data
+	void *private_data;
+};
+
+struct ArrowArray {
+	// Array data description
+	int64_t length;
+	int64_t null_count;
+	int64_t offset;
+	int64_t n_buffers;
+	int64_t n_children;
+	const void **buffers;
+	struct ArrowArray **children;
+	struct ArrowArray *dictionary;
+
+	// Release callback
+	void (*release)(struct ArrowArray *);
+	// Opaque producer-specific data
+	void *private_data;
+};
+
+#endif // ARROW_C_DATA_INTERFACE
+
+struct ArrowSchema *bridge_new_arrow_schema(void);
+void bridge_free_arrow_schema(struct ArrowSchema *schema);
+struct ArrowArray *bridge_new_arrow_array(void);
+void bridge_free_arrow_array(struct ArrowArray *array);
+
+#endif
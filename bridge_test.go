@@ -0,0 +1,137 @@
+package bridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestBridge(t *testing.T) {
+	b, err := NewBridge(pytasks.GetPythonSingleton())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ImportTable", func(t *testing.T) {
+		table, err := b.ImportTable("foo", "zero_copy_chunks", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer table.Release()
+
+		if got, want := table.NumRows(), int64(8); got != want {
+			t.Fatalf("got=%d rows, want=%d", got, want)
+		}
+		testColumn(t, table, 1, "utf8", []string{`["foo" "bar" "baz" (null)]`, `["foo" "bar" "baz" (null)]`})
+	})
+
+	t.Run("ImportRecordBatch", func(t *testing.T) {
+		rec, err := b.ImportRecordBatch("foo", "record_batch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rec.Release()
+
+		if got, want := fmt.Sprintf("%v", rec.Column(0)), "[1 (null) 3]"; got != want {
+			t.Fatalf("got=%s, want=%s", got, want)
+		}
+	})
+
+	t.Run("Go table argument", func(t *testing.T) {
+		table, err := b.ImportTable("foo", "zero_copy_chunks", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer table.Release()
+
+		names, err := b.ImportArray("foo", "column_names", table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer names.Release()
+
+		if got, want := fmt.Sprintf("%v", names), `["f0" "f1" "f2"]`; got != want {
+			t.Fatalf("got=%s, want=%s", got, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := b.ImportTable("foo", "missing_function")
+		perr, ok := err.(*PythonError)
+		if !ok {
+			t.Fatalf("got error=%v of type %T, want=*PythonError", err, err)
+		}
+		if want := "could not call foo.missing_function"; perr.Op != want {
+			t.Fatalf("got op=%q, want=%q", perr.Op, want)
+		}
+
+		_, err = b.ImportTable("foo", "identity", struct{}{})
+		if want := "argument 0: can not pass a struct {} to Python"; err == nil || err.Error() != want {
+			t.Fatalf("got error=%v, want=%s", err, want)
+		}
+	})
+}
+
+func TestCheckGIL(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic without the GIL")
+		}
+	}()
+
+	// The test goroutine does not hold the GIL, checkGIL panics before
+	// anything touches the table.
+	var table array.Table
+	table, _ = PyTableToTable(nil)
+	t.Fatalf("got table=%v, want a panic", table)
+}
+
+func TestCheckGILEntryPoints(t *testing.T) {
+	// None of the functions gets far enough to use its nil arguments.
+	for name, fn := range map[string]func(){
+		"PyChunkedGetPyChunks": func() { PyChunkedGetPyChunks(nil) },
+		"PyChunksGetChunk":     func() { PyChunksGetChunk(nil, 0, arrow.PrimitiveTypes.Int64) },
+		"PyChunkToData":        func() { PyChunkToData(nil, arrow.PrimitiveTypes.Int64) },
+		"PyChunkGetChildData":  func() { PyChunkGetChildData(nil, arrow.PrimitiveTypes.Int64) },
+		"PyChunkGetBuffers":    func() { PyChunkGetBuffers(nil) },
+		"PyBuffersToBuffers":   func() { PyBuffersToBuffers(nil) },
+		"PyColumnGetPyChunked": func() { PyColumnGetPyChunked(nil) },
+		"PyFieldToField":       func() { PyFieldToField(nil) },
+		"PyDataTypeGetType":    func() { PyDataTypeGetType(nil) },
+		"DataTypeToPyDataType": func() { DataTypeToPyDataType(arrow.PrimitiveTypes.Int64) },
+		"MetadataToPyMetadata": func() { MetadataToPyMetadata(arrow.NewMetadata([]string{"k"}, []string{"v"})) },
+	} {
+		if !panics(fn) {
+			t.Errorf("%s did not panic without the GIL", name)
+		}
+	}
+}
+
+func TestGoValueToPyObject(t *testing.T) {
+	err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		for _, v := range []interface{}{nil, true, 1, int64(2), 3.5, "a", []byte("b")} {
+			pyObj, err := goValueToPyObject(v)
+			if err != nil {
+				t.Errorf("%#v: %v", v, err)
+				continue
+			}
+			pyObj.DecRef()
+		}
+
+		// PyUnicode_FromString fails on invalid UTF-8.
+		_, err := goValueToPyObject("\xff")
+		perr, ok := err.(*PythonError)
+		if !ok {
+			t.Fatalf("got error=%v of type %T, want=*PythonError", err, err)
+		}
+		if want := "could not convert a string to Python"; perr.Op != want || perr.Type != "UnicodeDecodeError" {
+			t.Fatalf("got op=%q type=%q, want op=%q type=UnicodeDecodeError", perr.Op, perr.Type, want)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
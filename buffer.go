@@ -1,13 +1,28 @@
 package bridge
 
+// #include "bridge.h"
+import "C"
+
 import (
 	"errors"
+	"unsafe"
 
 	"github.com/DataDog/go-python3"
 	"github.com/apache/arrow/go/arrow/memory"
 )
 
+// PyBuffersToBuffers converts a list of pyarrow Buffers to Go buffers. None
+// items, e.g. absent validity bitmaps, become nil buffers. The returned
+// buffers must be Release()'d after use.
 func PyBuffersToBuffers(pyBuffers *python3.PyObject) ([]*memory.Buffer, error) {
+	checkGIL()
+
+	return pyBuffersToBuffers(pyBuffers, -1, nil)
+}
+
+// pyBuffersToBuffers converts the first n buffers of pyBuffers, or all of
+// them when n is negative, see newForeignBuffer for accounting.
+func pyBuffersToBuffers(pyBuffers *python3.PyObject, n int, accounting memory.Allocator) ([]*memory.Buffer, error) {
 	// First buffer is the null mask buffer, second is the values.
 	// [<pyarrow.lib.Buffer object at 0x113d46a08>, <pyarrow.lib.Buffer object at 0x114761998>]
 	if !python3.PyList_Check(pyBuffers) {
@@ -15,11 +30,15 @@ func PyBuffersToBuffers(pyBuffers *python3.PyObject) ([]*memory.Buffer, error) {
 	}
 
 	length := python3.PyList_Size(pyBuffers)
+	if n >= 0 && n < length {
+		length = n
+	}
 	buffers := make([]*memory.Buffer, 0, length)
 	for i := 0; i < length; i++ {
-		buffer, err := PyBuffersGetBuffer(pyBuffers, i)
+		buffer, err := pyBuffersGetBuffer(pyBuffers, i, accounting)
 		if err != nil {
-			return nil, err
+			releaseBuffers(buffers)
+			return nil, withPath(err, "buffer %d", i)
 		}
 		// buffers[i] = buffer
 		buffers = append(buffers, buffer)
@@ -28,35 +47,150 @@ func PyBuffersToBuffers(pyBuffers *python3.PyObject) ([]*memory.Buffer, error) {
 	return buffers, nil
 }
 
+// PyBuffersGetBuffer returns the Go buffer for the item at index i of the
+// pyBuffers list, or nil if the item is None, e.g. an absent validity bitmap.
+// The returned buffer must be Release()'d after use.
 func PyBuffersGetBuffer(pyBuffers *python3.PyObject, i int) (*memory.Buffer, error) {
-	// Get the buffer at index i
+	checkGIL()
+
+	return pyBuffersGetBuffer(pyBuffers, i, nil)
+}
+
+func pyBuffersGetBuffer(pyBuffers *python3.PyObject, i int, accounting memory.Allocator) (*memory.Buffer, error) {
+	// Get the buffer at index i, this is a borrowed reference
 	pyBuffer := python3.PyList_GetItem(pyBuffers, i)
 	if pyBuffer == nil {
-		return nil, errors.New("could not get pyBuffer")
+		return nil, pyError("could not get pyBuffer")
+	}
+	if pyBuffer == python3.Py_None {
+		return nil, nil
 	}
-	defer pyBuffer.DecRef()
 
-	goBytes, err := PyBufferToBytes(pyBuffer)
-	if err != nil {
-		return nil, err
+	return pyBufferToBuffer(pyBuffer, accounting)
+}
+
+// releaseBuffers releases the buffers, skipping the nil ones.
+func releaseBuffers(buffers []*memory.Buffer) {
+	for _, b := range buffers {
+		if b != nil {
+			b.Release()
+		}
 	}
+}
 
-	buffer := memory.NewBufferBytes(goBytes)
-	return buffer, nil
+// PyBufferToBuffer returns a Go buffer sharing the memory of the Python
+// object pyBuffer without copying it. The buffer holds a view of pyBuffer,
+// which keeps the exporting object alive. Once the buffer is released the
+// view is released under the GIL, so the buffer must be Release()'d before
+// the interpreter is finalized.
+func PyBufferToBuffer(pyBuffer *python3.PyObject) (*memory.Buffer, error) {
+	checkGIL()
+
+	return pyBufferToBuffer(pyBuffer, nil)
 }
 
+func pyBufferToBuffer(pyBuffer *python3.PyObject, accounting memory.Allocator) (*memory.Buffer, error) {
+	// <pyarrow.lib.Buffer object at 0x113d46a08>
+	view := C.bridge_get_buffer((*C.PyObject)(unsafe.Pointer(pyBuffer)))
+	if view == nil {
+		return nil, pyError("could not get pyBuf")
+	}
+
+	return newViewBuffer(view, accounting), nil
+}
+
+// newViewBuffer returns a Go buffer sharing the memory of the view, which
+// is released under the GIL once the buffer is released.
+func newViewBuffer(view *C.Py_buffer, accounting memory.Allocator) *memory.Buffer {
+	goBytes := cBytes(view.buf, int(view.len))
+	release := func() {
+		withGIL(func() {
+			C.bridge_release_buffer(view)
+		})
+	}
+	return newForeignBuffer(goBytes, release, accounting)
+}
+
+// PyBufferToBytes returns the bytes of pyBuffer without copying them.
+//
+// Deprecated: the view taken of pyBuffer is never released and nothing ties
+// the lifetime of the returned bytes to pyBuffer. Use PyBufferToBuffer.
 func PyBufferToBytes(pyBuffer *python3.PyObject) ([]byte, error) {
+	checkGIL()
+
 	// <pyarrow.lib.Buffer object at 0x113d46a08>
 	// Convert the buffer to our Py_buffer struct type
 	pyBuf, err := python3.PyObject_GetBuffer(pyBuffer, python3.PyBUF_SIMPLE)
 	if err {
-		return nil, errors.New("could not get pyBuf")
+		return nil, pyError("could not get pyBuf")
 	}
 
 	goBytes := python3.PyObject_GetBufferBytes(pyBuf)
 	if goBytes == nil {
-		return nil, errors.New("could not get goBytes")
+		return nil, pyError("could not get goBytes")
 	}
 
 	return goBytes, nil
 }
+
+// BuffersToPyBuffers returns a Python list of pyarrow Buffers wrapping the Go buffers.
+func BuffersToPyBuffers(buffers []*memory.Buffer) (*python3.PyObject, error) {
+	checkGIL()
+
+	return buffersToPyBuffers(buffers)
+}
+
+func buffersToPyBuffers(buffers []*memory.Buffer) (*python3.PyObject, error) {
+	pyBuffers := make([]*python3.PyObject, 0, len(buffers))
+	defer func() {
+		for i := range pyBuffers {
+			pyBuffers[i].DecRef()
+		}
+	}()
+
+	for _, buffer := range buffers {
+		pyBuffer, err := bufferToPyBuffer(buffer)
+		if err != nil {
+			return nil, err
+		}
+		pyBuffers = append(pyBuffers, pyBuffer)
+	}
+
+	return NewPyList(pyBuffers), nil
+}
+
+// BufferToPyBuffer wraps the Go buffer in a pyarrow Buffer without copying.
+// The Go buffer is retained until Python releases the pyarrow Buffer.
+func BufferToPyBuffer(buffer *memory.Buffer) (*python3.PyObject, error) {
+	checkGIL()
+
+	return bufferToPyBuffer(buffer)
+}
+
+func bufferToPyBuffer(buffer *memory.Buffer) (*python3.PyObject, error) {
+	if buffer == nil {
+		python3.Py_None.IncRef()
+		return python3.Py_None, nil
+	}
+
+	goBytes := buffer.Bytes()
+	if len(goBytes) == 0 {
+		pyBytes := python3.PyBytes_FromString("")
+		defer pyBytes.DecRef()
+		return callPyArrowFunc("py_buffer", pyBytes)
+	}
+
+	pyBase, err := newGoBufferCapsule(buffer)
+	if err != nil {
+		return nil, err
+	}
+	defer pyBase.DecRef()
+
+	pyAddress := python3.PyLong_FromUnsignedLongLong(uint64(uintptr(unsafe.Pointer(&goBytes[0]))))
+	defer pyAddress.DecRef()
+
+	pySize := python3.PyLong_FromLong(len(goBytes))
+	defer pySize.DecRef()
+
+	return callPyArrowFunc("foreign_buffer", pyAddress, pySize, pyBase)
+}
@@ -0,0 +1,80 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestBufferLifetime(t *testing.T) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	py := pytasks.GetPythonSingleton()
+	allocated := func() int {
+		var n int
+		err := py.NewTaskSync(func() {
+			pyN := CallPyFunc(fooModule, "total_allocated_bytes")
+			if pyN == nil {
+				t.Error("could not get total_allocated_bytes")
+				return
+			}
+			defer pyN.DecRef()
+			n = python3.PyLong_AsLong(pyN)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	before := allocated()
+
+	numElements := 1000
+	var table array.Table
+	var err error
+	taskErr := py.NewTaskSync(func() {
+		pyNumElements := python3.PyLong_FromLong(numElements)
+		defer pyNumElements.DecRef()
+		pyTable := CallPyFunc(fooModule, "nullable_elements", pyNumElements)
+		if pyTable == nil {
+			t.Error("pyTable is nil")
+			return
+		}
+		table, err = PyTableToTable(pyTable)
+		// Drop the only Python reference to the table.
+		pyTable.DecRef()
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table == nil {
+		t.FailNow()
+	}
+
+	if got := allocated(); got <= before {
+		t.Fatalf("python memory was freed while Go still references it: got=%d, before=%d", got, before)
+	}
+
+	// The memory is still owned by Python and must still be readable.
+	f1 := table.Column(1).Data().Chunk(0).(*array.String)
+	for i := 0; i < numElements; i++ {
+		if got, want := f1.Value(i), "foo"; got != want {
+			t.Fatalf("got=%q, want=%q", got, want)
+		}
+	}
+	if !f1.IsNull(numElements) {
+		t.Fatalf("value %d should be null", numElements)
+	}
+
+	// Releasing the Go table releases the Python memory under the GIL.
+	table.Release()
+	if got := allocated(); got != before {
+		t.Fatalf("python memory was not released: got=%d, want=%d", got, before)
+	}
+}
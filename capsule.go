@@ -0,0 +1,53 @@
+package bridge
+
+// #cgo pkg-config: python3
+// #include "bridge.h"
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// goBuffers keeps Go buffers that have been handed to Python alive until
+// Python drops its last reference to them. C must not hold on to Go
+// pointers so Python only ever sees the handle.
+var goBuffers = struct {
+	sync.Mutex
+	next    uintptr
+	handles map[uintptr]*memory.Buffer
+}{handles: make(map[uintptr]*memory.Buffer)}
+
+// newGoBufferCapsule retains buf and returns a PyCapsule that releases it
+// when the capsule is garbage collected by Python.
+func newGoBufferCapsule(buf *memory.Buffer) (*python3.PyObject, error) {
+	buf.Retain()
+
+	goBuffers.Lock()
+	goBuffers.next++
+	handle := goBuffers.next
+	goBuffers.handles[handle] = buf
+	goBuffers.Unlock()
+
+	capsule := C.bridge_new_capsule(C.uintptr_t(handle))
+	if capsule == nil {
+		goReleaseBuffer(C.uintptr_t(handle))
+		return nil, pyError("could not create capsule")
+	}
+	return (*python3.PyObject)(unsafe.Pointer(capsule)), nil
+}
+
+//export goReleaseBuffer
+func goReleaseBuffer(handle C.uintptr_t) {
+	goBuffers.Lock()
+	buf := goBuffers.handles[uintptr(handle)]
+	delete(goBuffers.handles, uintptr(handle))
+	goBuffers.Unlock()
+
+	if buf != nil {
+		buf.Release()
+	}
+}
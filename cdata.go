@@ -0,0 +1,714 @@
+package bridge
+
+// #include "bridge.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// The functions in this file move data across the language boundary with
+// the Arrow C data interface instead of walking the pyarrow objects
+// attribute by attribute. A single _export_to_c call describes a whole
+// array, including its buffers and children, so the number of cgo calls no
+// longer grows with the number of buffers in a chunk.
+//
+// The C data interface requires pyarrow >= 0.17.
+
+// PyTableToTableCData converts a pyarrow Table to a Go Table using the
+// Arrow C data interface. Each record batch of the table crosses the
+// boundary with a single call.
+func PyTableToTableCData(pyTable *python3.PyObject) (array.Table, error) {
+	checkGIL()
+
+	pySchema, err := pySchemaFromPyTable(pyTable)
+	if err != nil {
+		return nil, err
+	}
+	defer pySchema.DecRef()
+
+	schema, err := pySchemaToSchemaCData(pySchema)
+	if err != nil {
+		return nil, err
+	}
+
+	pyBatches := CallPyFunc(pyTable, "to_batches")
+	if pyBatches == nil {
+		return nil, pyError("could not get pyBatches")
+	}
+	defer pyBatches.DecRef()
+
+	if !python3.PyList_Check(pyBatches) {
+		return nil, errors.New("pyBatches is not a list")
+	}
+
+	length := python3.PyList_Size(pyBatches)
+	recs := make([]array.Record, 0, length)
+	defer func() {
+		for i := range recs {
+			recs[i].Release()
+		}
+	}()
+
+	for i := 0; i < length; i++ {
+		pyBatch := python3.PyList_GetItem(pyBatches, i)
+		if pyBatch == nil {
+			return nil, pyError("could not get pyBatch")
+		}
+
+		rec, err := pyRecordBatchToRecordCData(pyBatch, schema)
+		if err != nil {
+			return nil, withPath(err, "batch %d", i)
+		}
+		recs = append(recs, rec)
+	}
+
+	return array.NewTableFromRecords(schema, recs), nil
+}
+
+// PyChunkedToChunkedCData converts a pyarrow ChunkedArray to a Go Chunked
+// using the Arrow C data interface.
+func PyChunkedToChunkedCData(pyChunked *python3.PyObject) (*array.Chunked, error) {
+	checkGIL()
+
+	pyDtype := pyAttr(pyChunked, "type")
+	if pyDtype == nil {
+		return nil, pyError("could not get pyDtype")
+	}
+	defer pyDtype.DecRef()
+
+	dtype, err := pyDataTypeToDataTypeCData(pyDtype)
+	if err != nil {
+		return nil, err
+	}
+
+	pyChunks, err := pyChunkedGetPyChunks(pyChunked)
+	if err != nil {
+		return nil, err
+	}
+	defer pyChunks.DecRef()
+
+	if !python3.PyList_Check(pyChunks) {
+		return nil, errors.New("pyChunks is not a list")
+	}
+
+	length := python3.PyList_Size(pyChunks)
+	chunks := make([]array.Interface, 0, length)
+	defer func() {
+		for i := range chunks {
+			chunks[i].Release()
+		}
+	}()
+
+	for i := 0; i < length; i++ {
+		pyChunk := python3.PyList_GetItem(pyChunks, i)
+		if pyChunk == nil {
+			return nil, pyError("could not get pyChunk from list")
+		}
+
+		data, err := pyArrayToDataCData(pyChunk, dtype)
+		if err != nil {
+			return nil, withPath(err, "chunk %d", i)
+		}
+		chunks = append(chunks, makeFromData(data))
+		data.Release()
+	}
+
+	return array.NewChunked(dtype, chunks), nil
+}
+
+// PyArrayToArrayCData converts a pyarrow Array to a Go array using the
+// Arrow C data interface.
+func PyArrayToArrayCData(pyArray *python3.PyObject) (array.Interface, error) {
+	checkGIL()
+
+	data, err := PyArrayToDataCData(pyArray)
+	if err != nil {
+		return nil, err
+	}
+	defer data.Release()
+	return makeFromData(data), nil
+}
+
+// PyArrayToDataCData converts a pyarrow Array to Go array Data using the
+// Arrow C data interface.
+func PyArrayToDataCData(pyArray *python3.PyObject) (*array.Data, error) {
+	checkGIL()
+
+	cSchema := C.bridge_new_arrow_schema()
+	defer C.bridge_free_arrow_schema(cSchema)
+
+	owner := newCDataOwner()
+	defer owner.release()
+
+	err := pyExportToC(pyArray, unsafe.Pointer(owner.array), unsafe.Pointer(cSchema))
+	if err != nil {
+		return nil, err
+	}
+
+	dtype, err := importCDataType(cSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return importCDataArray(owner.array, dtype, owner)
+}
+
+// pyArrayToDataCData converts a pyarrow Array of a known type to Go array
+// Data, skipping the export of the type.
+func pyArrayToDataCData(pyArray *python3.PyObject, dtype arrow.DataType) (*array.Data, error) {
+	owner := newCDataOwner()
+	defer owner.release()
+
+	if err := pyExportToC(pyArray, unsafe.Pointer(owner.array)); err != nil {
+		return nil, err
+	}
+
+	return importCDataArray(owner.array, dtype, owner)
+}
+
+// PySchemaToSchemaCData given a Python schema gets the Go Arrow schema
+// using the Arrow C data interface.
+func PySchemaToSchemaCData(pySchema *python3.PyObject) (*arrow.Schema, error) {
+	checkGIL()
+
+	return pySchemaToSchemaCData(pySchema)
+}
+
+func pySchemaToSchemaCData(pySchema *python3.PyObject) (*arrow.Schema, error) {
+	cSchema := C.bridge_new_arrow_schema()
+	defer C.bridge_free_arrow_schema(cSchema)
+
+	if err := pyExportToC(pySchema, unsafe.Pointer(cSchema)); err != nil {
+		return nil, err
+	}
+
+	return importCDataSchema(cSchema)
+}
+
+// PyDataTypeToDataTypeCData returns the Go arrow DataType given the Python
+// type using the Arrow C data interface.
+func PyDataTypeToDataTypeCData(pyDtype *python3.PyObject) (arrow.DataType, error) {
+	checkGIL()
+
+	return pyDataTypeToDataTypeCData(pyDtype)
+}
+
+func pyDataTypeToDataTypeCData(pyDtype *python3.PyObject) (arrow.DataType, error) {
+	cSchema := C.bridge_new_arrow_schema()
+	defer C.bridge_free_arrow_schema(cSchema)
+
+	if err := pyExportToC(pyDtype, unsafe.Pointer(cSchema)); err != nil {
+		return nil, err
+	}
+
+	return importCDataType(cSchema)
+}
+
+// pyRecordBatchToRecordCData converts a pyarrow RecordBatch with the given
+// schema to a Go Record. The batch is exported as a single struct array.
+func pyRecordBatchToRecordCData(pyBatch *python3.PyObject, schema *arrow.Schema) (array.Record, error) {
+	owner := newCDataOwner()
+	defer owner.release()
+
+	if err := pyExportToC(pyBatch, unsafe.Pointer(owner.array)); err != nil {
+		return nil, err
+	}
+
+	data, err := importCDataArray(owner.array, arrow.StructOf(schema.Fields()...), owner)
+	if err != nil {
+		return nil, err
+	}
+	defer data.Release()
+
+	batch := array.NewStructData(data)
+	defer batch.Release()
+
+	cols := make([]array.Interface, 0, batch.NumField())
+	for i := 0; i < batch.NumField(); i++ {
+		cols = append(cols, batch.Field(i))
+	}
+
+	return array.NewRecord(schema, cols, int64(batch.Len())), nil
+}
+
+// pyExportToC calls _export_to_c on the pyarrow object with the addresses
+// of the C structs to export into.
+func pyExportToC(pyObj *python3.PyObject, ptrs ...unsafe.Pointer) error {
+	if !pyObj.HasAttrString("_export_to_c") {
+		return errors.New("the Arrow C data interface requires pyarrow >= 0.17")
+	}
+
+	args := make([]*python3.PyObject, 0, len(ptrs))
+	defer func() {
+		for i := range args {
+			args[i].DecRef()
+		}
+	}()
+	for _, ptr := range ptrs {
+		args = append(args, python3.PyLong_FromUnsignedLongLong(uint64(uintptr(ptr))))
+	}
+
+	v := CallPyFunc(pyObj, "_export_to_c", args...)
+	if v == nil {
+		return pyError("could not call _export_to_c")
+	}
+	v.DecRef()
+	return nil
+}
+
+// cdataOwner owns an exported ArrowArray. Every Go buffer that views memory
+// of the exported array holds a reference to the owner and the array is
+// released once the last of them is released.
+type cdataOwner struct {
+	refCount int64
+	array    *C.struct_ArrowArray
+}
+
+func newCDataOwner() *cdataOwner {
+	return &cdataOwner{refCount: 1, array: C.bridge_new_arrow_array()}
+}
+
+func (o *cdataOwner) retain() {
+	atomic.AddInt64(&o.refCount, 1)
+}
+
+func (o *cdataOwner) release() {
+	if atomic.AddInt64(&o.refCount, -1) == 0 {
+		C.bridge_free_arrow_array(o.array)
+		o.array = nil
+	}
+}
+
+// buffer returns a Go buffer viewing size bytes at ptr, or nil when ptr
+// is NULL. The returned buffer must be Release()'d after use.
+func (o *cdataOwner) buffer(ptr unsafe.Pointer, size int) *memory.Buffer {
+	if ptr == nil {
+		return nil
+	}
+	o.retain()
+	return newForeignBuffer(cBytes(ptr, size), o.release, nil)
+}
+
+func importCDataSchema(cSchema *C.struct_ArrowSchema) (*arrow.Schema, error) {
+	format := C.GoString(cSchema.format)
+	if format != "+s" {
+		return nil, fmt.Errorf("schema must be exported as a struct, got format %q", format)
+	}
+
+	fields, err := importCDataFields(cSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := importCDataMetadata(cSchema.metadata)
+	return arrow.NewSchema(fields, &metadata), nil
+}
+
+func importCDataFields(cSchema *C.struct_ArrowSchema) ([]arrow.Field, error) {
+	children := cSchemaChildren(cSchema)
+	fields := make([]arrow.Field, 0, len(children))
+	for _, child := range children {
+		field, err := importCDataField(child)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	if err := checkUniqueNames(fields, nil); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func importCDataField(cSchema *C.struct_ArrowSchema) (arrow.Field, error) {
+	dtype, err := importCDataType(cSchema)
+	if err != nil {
+		return arrow.Field{}, err
+	}
+	if hasDictionaryChild(dtype) {
+		return arrow.Field{}, &UnsupportedTypeError{Type: fmt.Sprintf("%v", dtype), Python: true}
+	}
+
+	return arrow.Field{
+		Name:     C.GoString(cSchema.name),
+		Type:     dtype,
+		Nullable: cSchema.flags&C.ARROW_FLAG_NULLABLE != 0,
+		Metadata: importCDataMetadata(cSchema.metadata),
+	}, nil
+}
+
+var cdataPrimitiveTypes = map[string]arrow.DataType{
+	"n":   arrow.Null,
+	"b":   arrow.FixedWidthTypes.Boolean,
+	"c":   arrow.PrimitiveTypes.Int8,
+	"C":   arrow.PrimitiveTypes.Uint8,
+	"s":   arrow.PrimitiveTypes.Int16,
+	"S":   arrow.PrimitiveTypes.Uint16,
+	"i":   arrow.PrimitiveTypes.Int32,
+	"I":   arrow.PrimitiveTypes.Uint32,
+	"l":   arrow.PrimitiveTypes.Int64,
+	"L":   arrow.PrimitiveTypes.Uint64,
+	"e":   arrow.FixedWidthTypes.Float16,
+	"f":   arrow.PrimitiveTypes.Float32,
+	"g":   arrow.PrimitiveTypes.Float64,
+	"z":   arrow.BinaryTypes.Binary,
+	"u":   arrow.BinaryTypes.String,
+	"tdD": arrow.PrimitiveTypes.Date32,
+	"tdm": arrow.PrimitiveTypes.Date64,
+	"tts": arrow.FixedWidthTypes.Time32s,
+	"ttm": arrow.FixedWidthTypes.Time32ms,
+	"ttu": arrow.FixedWidthTypes.Time64us,
+	"ttn": arrow.FixedWidthTypes.Time64ns,
+	"tDs": arrow.FixedWidthTypes.Duration_s,
+	"tDm": arrow.FixedWidthTypes.Duration_ms,
+	"tDu": arrow.FixedWidthTypes.Duration_us,
+	"tDn": arrow.FixedWidthTypes.Duration_ns,
+	"tiM": arrow.FixedWidthTypes.MonthInterval,
+	"tiD": arrow.FixedWidthTypes.DayTimeInterval,
+}
+
+var cdataTimeUnits = map[byte]arrow.TimeUnit{
+	's': arrow.Second,
+	'm': arrow.Millisecond,
+	'u': arrow.Microsecond,
+	'n': arrow.Nanosecond,
+}
+
+func importCDataType(cSchema *C.struct_ArrowSchema) (arrow.DataType, error) {
+	format := C.GoString(cSchema.format)
+
+	if cSchema.dictionary != nil {
+		indexType, ok := cdataPrimitiveTypes[format]
+		if !ok {
+			return nil, fmt.Errorf("invalid dictionary index format %q", format)
+		}
+		valueType, err := importCDataType(cSchema.dictionary)
+		if err != nil {
+			return nil, err
+		}
+		return &DictionaryType{
+			IndexType: indexType,
+			ValueType: valueType,
+			Ordered:   cSchema.flags&C.ARROW_FLAG_DICTIONARY_ORDERED != 0,
+		}, nil
+	}
+
+	if dtype, ok := cdataPrimitiveTypes[format]; ok {
+		return dtype, nil
+	}
+
+	switch {
+	case strings.HasPrefix(format, "w:"):
+		byteWidth, err := strconv.Atoi(format[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixed size binary format %q", format)
+		}
+		return &arrow.FixedSizeBinaryType{ByteWidth: byteWidth}, nil
+
+	case strings.HasPrefix(format, "d:"):
+		parts := strings.Split(format[2:], ",")
+		if len(parts) == 3 && parts[2] != "128" {
+			return nil, &UnsupportedTypeError{Type: format, Python: true}
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid decimal format %q", format)
+		}
+		precision, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid decimal format %q", format)
+		}
+		scale, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid decimal format %q", format)
+		}
+		return &arrow.Decimal128Type{Precision: int32(precision), Scale: int32(scale)}, nil
+
+	case strings.HasPrefix(format, "ts") && len(format) >= 4 && format[3] == ':':
+		unit, ok := cdataTimeUnits[format[2]]
+		if !ok {
+			return nil, fmt.Errorf("invalid timestamp format %q", format)
+		}
+		return &arrow.TimestampType{Unit: unit, TimeZone: format[4:]}, nil
+
+	case format == "+l":
+		children := cSchemaChildren(cSchema)
+		if len(children) != 1 {
+			return nil, fmt.Errorf("list format %q must have exactly one child", format)
+		}
+		elem, err := importCDataType(children[0])
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elem), nil
+
+	case format == "+m":
+		// Go arrow has no map type. A map is laid out as a list of
+		// key/value structs so that is what it becomes in Go.
+		children := cSchemaChildren(cSchema)
+		if len(children) != 1 {
+			return nil, fmt.Errorf("map format %q must have exactly one child", format)
+		}
+		elem, err := importCDataType(children[0])
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elem), nil
+
+	case strings.HasPrefix(format, "+w:"):
+		n, err := strconv.Atoi(format[3:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixed size list format %q", format)
+		}
+		children := cSchemaChildren(cSchema)
+		if len(children) != 1 {
+			return nil, fmt.Errorf("fixed size list format %q must have exactly one child", format)
+		}
+		elem, err := importCDataType(children[0])
+		if err != nil {
+			return nil, err
+		}
+		return arrow.FixedSizeListOf(int32(n), elem), nil
+
+	case format == "+s":
+		fields, err := importCDataFields(cSchema)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.StructOf(fields...), nil
+	}
+
+	return nil, &UnsupportedTypeError{Type: format, Python: true}
+}
+
+// importCDataMetadata decodes the metadata of an ArrowSchema. It is laid
+// out as an int32 number of pairs followed by length prefixed keys and
+// values.
+func importCDataMetadata(ptr *C.char) arrow.Metadata {
+	if ptr == nil {
+		return arrow.Metadata{}
+	}
+
+	base := unsafe.Pointer(ptr)
+	var pos uintptr
+	readInt32 := func() int {
+		v := *(*int32)(unsafe.Pointer(uintptr(base) + pos))
+		pos += 4
+		return int(v)
+	}
+	readString := func() string {
+		n := readInt32()
+		s := C.GoStringN((*C.char)(unsafe.Pointer(uintptr(base)+pos)), C.int(n))
+		pos += uintptr(n)
+		return s
+	}
+
+	n := readInt32()
+	keys := make([]string, 0, n)
+	values := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, readString())
+		values = append(values, readString())
+	}
+	return arrow.NewMetadata(keys, values)
+}
+
+func importCDataArray(cArray *C.struct_ArrowArray, dtype arrow.DataType, owner *cdataOwner) (*array.Data, error) {
+	length := int(cArray.length)
+	offset := int(cArray.offset)
+	nulls := int(cArray.null_count)
+
+	cBuffers := cArrayBuffers(cArray)
+	cChildren := cArrayChildren(cArray)
+
+	bitmapSize := (offset + length + 7) / 8
+
+	var buffers []*memory.Buffer
+	var childData []*array.Data
+	defer func() {
+		releaseBuffers(buffers)
+		for _, c := range childData {
+			c.Release()
+		}
+	}()
+
+	expectBuffers := func(n int) error {
+		if len(cBuffers) != n {
+			return fmt.Errorf("%v array exported %d buffers, expected %d", dtype, len(cBuffers), n)
+		}
+		return nil
+	}
+
+	importChildren := func(dtypes ...arrow.DataType) error {
+		if len(cChildren) != len(dtypes) {
+			return fmt.Errorf("%v array exported %d children, expected %d", dtype, len(cChildren), len(dtypes))
+		}
+		for i, child := range cChildren {
+			data, err := importCDataArray(child, dtypes[i], owner)
+			if err != nil {
+				return err
+			}
+			childData = append(childData, data)
+		}
+		return nil
+	}
+
+	switch dt := dtype.(type) {
+	case *arrow.NullType:
+		buffers = []*memory.Buffer{nil}
+
+	case *arrow.BooleanType:
+		if err := expectBuffers(2); err != nil {
+			return nil, err
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+			owner.buffer(cBuffers[1], bitmapSize),
+		}
+
+	case *DictionaryType:
+		if err := expectBuffers(2); err != nil {
+			return nil, err
+		}
+		if cArray.dictionary == nil {
+			return nil, fmt.Errorf("%v array exported no dictionary", dtype)
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+			owner.buffer(cBuffers[1], (offset+length)*dt.BitWidth()/8),
+		}
+		dictionary, err := importCDataArray(cArray.dictionary, dt.ValueType, owner)
+		if err != nil {
+			return nil, err
+		}
+		childData = append(childData, dictionary)
+
+	case *arrow.Decimal128Type:
+		if err := expectBuffers(2); err != nil {
+			return nil, err
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+			owner.buffer(cBuffers[1], (offset+length)*16),
+		}
+
+	case arrow.FixedWidthDataType:
+		if err := expectBuffers(2); err != nil {
+			return nil, err
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+			owner.buffer(cBuffers[1], (offset+length)*dt.BitWidth()/8),
+		}
+
+	case *arrow.BinaryType, *arrow.StringType:
+		if err := expectBuffers(3); err != nil {
+			return nil, err
+		}
+		offsets := owner.buffer(cBuffers[1], (offset+length+1)*arrow.Int32SizeBytes)
+		dataSize := 0
+		if offsets != nil {
+			dataSize = int(arrow.Int32Traits.CastFromBytes(offsets.Bytes())[offset+length])
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+			offsets,
+			owner.buffer(cBuffers[2], dataSize),
+		}
+
+	case *arrow.ListType:
+		if err := expectBuffers(2); err != nil {
+			return nil, err
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+			owner.buffer(cBuffers[1], (offset+length+1)*arrow.Int32SizeBytes),
+		}
+		if err := importChildren(dt.Elem()); err != nil {
+			return nil, err
+		}
+
+	case *arrow.FixedSizeListType:
+		if err := expectBuffers(1); err != nil {
+			return nil, err
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+		}
+		if err := importChildren(dt.Elem()); err != nil {
+			return nil, err
+		}
+
+	case *arrow.StructType:
+		if err := expectBuffers(1); err != nil {
+			return nil, err
+		}
+		buffers = []*memory.Buffer{
+			owner.buffer(cBuffers[0], bitmapSize),
+		}
+		dtypes := make([]arrow.DataType, 0, len(dt.Fields()))
+		for _, f := range dt.Fields() {
+			dtypes = append(dtypes, f.Type)
+		}
+		if err := importChildren(dtypes...); err != nil {
+			return nil, err
+		}
+		// Go struct arrays do not apply their offset to their children.
+		if offset != 0 {
+			for i, child := range childData {
+				childData[i] = array.NewSliceData(child, int64(offset), int64(offset+length))
+				child.Release()
+			}
+		}
+
+	default:
+		return nil, &UnsupportedTypeError{Type: fmt.Sprint(dtype), Python: false}
+	}
+
+	return array.NewData(dtype, length, buffers, childData, nulls, offset), nil
+}
+
+func cSchemaChildren(cSchema *C.struct_ArrowSchema) []*C.struct_ArrowSchema {
+	n := int(cSchema.n_children)
+	if n == 0 {
+		return nil
+	}
+	return (*[1 << 28]*C.struct_ArrowSchema)(unsafe.Pointer(cSchema.children))[:n:n]
+}
+
+func cArrayChildren(cArray *C.struct_ArrowArray) []*C.struct_ArrowArray {
+	n := int(cArray.n_children)
+	if n == 0 {
+		return nil
+	}
+	return (*[1 << 28]*C.struct_ArrowArray)(unsafe.Pointer(cArray.children))[:n:n]
+}
+
+func cArrayBuffers(cArray *C.struct_ArrowArray) []unsafe.Pointer {
+	n := int(cArray.n_buffers)
+	if n == 0 {
+		return nil
+	}
+	return (*[1 << 28]unsafe.Pointer)(unsafe.Pointer(cArray.buffers))[:n:n]
+}
+
+// cBytes returns a slice viewing n bytes of C memory at ptr without copying.
+func cBytes(ptr unsafe.Pointer, n int) []byte {
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = uintptr(ptr)
+	h.Len = n
+	h.Cap = n
+	return b
+}
@@ -9,19 +9,57 @@ import (
 	"github.com/apache/arrow/go/arrow/memory"
 )
 
+// PyChunkedArrayToChunked converts a pyarrow ChunkedArray to a Go Chunked,
+// inferring the Go type from pyChunked.type.
+func PyChunkedArrayToChunked(pyChunked *python3.PyObject) (*array.Chunked, error) {
+	return defaultConverter.PyChunkedArrayToChunked(pyChunked)
+}
+
+// PyChunkedArrayToChunked is like the package level function with the
+// options of c.
+func (c *Converter) PyChunkedArrayToChunked(pyChunked *python3.PyObject) (*array.Chunked, error) {
+	checkGIL()
+
+	dtype, err := pyObjectGetDataType(pyChunked)
+	if err != nil {
+		return nil, err
+	}
+	return c.pyChunkedToChunked(pyChunked, dtype)
+}
+
 func PyChunkedToChunked(pyChunked *python3.PyObject, dtype arrow.DataType) (*array.Chunked, error) {
+	checkGIL()
+
+	return defaultConverter.pyChunkedToChunked(pyChunked, dtype)
+}
+
+func (c *Converter) pyChunkedToChunked(pyChunked *python3.PyObject, dtype arrow.DataType) (*array.Chunked, error) {
 	// Convert pyChunks to []Interface
-	chunks, err := PyChunkedToChunks(pyChunked, dtype)
+	chunks, err := c.pyChunkedToChunks(pyChunked, dtype)
 	if err != nil {
 		return nil, err
 	}
 
+	// NewChunked retains the chunks it is given
 	chunked := array.NewChunked(dtype, chunks)
+	for _, chunk := range chunks {
+		chunk.Release()
+	}
 	return chunked, nil
 }
 
 func PyChunkedToChunks(pyChunked *python3.PyObject, dtype arrow.DataType) ([]array.Interface, error) {
-	pyChunks, err := PyChunkedGetPyChunks(pyChunked)
+	checkGIL()
+
+	return defaultConverter.pyChunkedToChunks(pyChunked, dtype)
+}
+
+func (c *Converter) pyChunkedToChunks(pyChunked *python3.PyObject, dtype arrow.DataType) ([]array.Interface, error) {
+	if c.copyMode == ZeroCopy && !c.rows && batchable(dtype) {
+		return c.pyChunkedToChunksBatched(pyChunked, dtype)
+	}
+
+	pyChunks, err := pyChunkedGetPyChunks(pyChunked)
 	if err != nil {
 		return nil, err
 	}
@@ -33,11 +71,27 @@ func PyChunkedToChunks(pyChunked *python3.PyObject, dtype arrow.DataType) ([]arr
 
 	length := python3.PyList_Size(pyChunks)
 	chunks := make([]array.Interface, 0, length)
-	for i := 0; i < length; i++ {
-		chunk, err := PyChunksGetChunk(pyChunks, i, dtype)
+	for i, pos := 0, 0; i < length; i++ {
+		var chunk array.Interface
+		var err error
+		if c.rows {
+			if pos >= c.rowOffset+c.rowLength {
+				// The chunks left are all after the selected rows.
+				break
+			}
+			chunk, pos, err = c.pyChunksGetChunkRows(pyChunks, i, pos, dtype)
+		} else {
+			chunk, err = c.pyChunksGetChunk(pyChunks, i, dtype)
+		}
 		if err != nil {
-			// TODO: Should release any chunks we've already got.
-			return nil, err
+			for _, chunk := range chunks {
+				chunk.Release()
+			}
+			return nil, withPath(err, "chunk %d", i)
+		}
+		if chunk == nil {
+			// None of the rows of the chunk are selected.
+			continue
 		}
 		chunks = append(chunks, chunk)
 	}
@@ -46,118 +100,520 @@ func PyChunkedToChunks(pyChunked *python3.PyObject, dtype arrow.DataType) ([]arr
 }
 
 func PyChunkedGetPyChunks(pyChunked *python3.PyObject) (*python3.PyObject, error) {
-	pyChunks := pyChunked.GetAttrString("chunks")
+	checkGIL()
+
+	return pyChunkedGetPyChunks(pyChunked)
+}
+
+func pyChunkedGetPyChunks(pyChunked *python3.PyObject) (*python3.PyObject, error) {
+	pyChunks := pyAttr(pyChunked, "chunks")
 	if pyChunks == nil {
-		return nil, errors.New("could not get pyChunks")
+		return nil, pyError("could not get pyChunks")
 	}
 	return pyChunks, nil
 }
 
 func PyChunksGetChunk(pyChunks *python3.PyObject, i int, dtype arrow.DataType) (array.Interface, error) {
-	pyChunk, err := PyChunksGetPyChunk(pyChunks, i)
+	checkGIL()
+
+	return defaultConverter.pyChunksGetChunk(pyChunks, i, dtype)
+}
+
+func (c *Converter) pyChunksGetChunk(pyChunks *python3.PyObject, i int, dtype arrow.DataType) (array.Interface, error) {
+	// pyChunk is borrowed from the list, it must not be DecRef()'d
+	pyChunk, err := pyChunksGetPyChunk(pyChunks, i)
 	if err != nil {
 		return nil, err
 	}
-	defer pyChunk.DecRef()
 
-	chunk, err := PyChunkToChunk(pyChunk, dtype)
+	chunk, err := c.pyChunkToChunk(pyChunk, dtype)
 	if err != nil {
 		return nil, err
 	}
 	return chunk, nil
 }
 
+// pyChunksGetChunkRows converts the rows selected by c of the chunk at
+// index i, which starts at row pos of the chunked array. The chunk is nil
+// if none of its rows are selected. The row after the chunk is returned.
+func (c *Converter) pyChunksGetChunkRows(pyChunks *python3.PyObject, i, pos int, dtype arrow.DataType) (array.Interface, int, error) {
+	// pyChunk is borrowed from the list, it must not be DecRef()'d
+	pyChunk, err := pyChunksGetPyChunk(pyChunks, i)
+	if err != nil {
+		return nil, pos, err
+	}
+
+	chunkLen, err := pyChunkGetLength(pyChunk)
+	if err != nil {
+		return nil, pos, err
+	}
+	if lo, hi := c.rowRange(pos, chunkLen); lo == hi {
+		return nil, pos + chunkLen, nil
+	}
+
+	pyRows, err := c.pySliceRowsAt(pyChunk, pos, chunkLen)
+	if err != nil {
+		return nil, pos, err
+	}
+	defer pyRows.DecRef()
+
+	chunk, err := c.pyChunkToChunk(pyRows, dtype)
+	if err != nil {
+		return nil, pos, err
+	}
+	return chunk, pos + chunkLen, nil
+}
+
+// PyChunksGetPyChunk returns the item at index i of the pyChunks list. The
+// returned reference is borrowed from the list.
 func PyChunksGetPyChunk(pyChunks *python3.PyObject, i int) (*python3.PyObject, error) {
+	checkGIL()
+
+	return pyChunksGetPyChunk(pyChunks, i)
+}
+
+func pyChunksGetPyChunk(pyChunks *python3.PyObject, i int) (*python3.PyObject, error) {
 	pyChunk := python3.PyList_GetItem(pyChunks, i)
 	if pyChunk == nil {
-		return nil, errors.New("could not get pyChunk from list")
+		return nil, pyError("could not get pyChunk from list")
 	}
 	return pyChunk, nil
 }
 
+// PyArrayToArray converts a pyarrow Array to a Go array, inferring the Go
+// type from pyArray.type. The returned array must be Release()'d after use.
+func PyArrayToArray(pyArray *python3.PyObject) (array.Interface, error) {
+	return defaultConverter.PyArrayToArray(pyArray)
+}
+
+// PyArrayToArray is like the package level function with the options of c.
+func (c *Converter) PyArrayToArray(pyArray *python3.PyObject) (array.Interface, error) {
+	checkGIL()
+
+	dtype, err := pyObjectGetDataType(pyArray)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.rows {
+		arrayLen, err := pyChunkGetLength(pyArray)
+		if err != nil {
+			return nil, err
+		}
+		pyRows, err := c.pySliceRows(pyArray, arrayLen)
+		if err != nil {
+			return nil, err
+		}
+		defer pyRows.DecRef()
+		pyArray = pyRows
+	}
+	return c.pyChunkToChunk(pyArray, dtype)
+}
+
 func PyChunkToChunk(pyChunk *python3.PyObject, dtype arrow.DataType) (array.Interface, error) {
-	data, err := PyChunkToData(pyChunk, dtype)
+	checkGIL()
+
+	return defaultConverter.pyChunkToChunk(pyChunk, dtype)
+}
+
+func (c *Converter) pyChunkToChunk(pyChunk *python3.PyObject, dtype arrow.DataType) (array.Interface, error) {
+	data, err := c.pyChunkToData(pyChunk, dtype)
 	if err != nil {
 		return nil, err
 	}
 	defer data.Release()
-	chunk := array.MakeFromData(data)
+	chunk := makeFromData(data)
 	return chunk, nil
 }
 
 func PyChunkToData(pyChunk *python3.PyObject, dtype arrow.DataType) (*array.Data, error) {
-	buffers, err := PyChunkGetBuffers(pyChunk)
+	checkGIL()
+
+	return defaultConverter.pyChunkToData(pyChunk, dtype)
+}
+
+func (c *Converter) pyChunkToData(pyChunk *python3.PyObject, dtype arrow.DataType) (*array.Data, error) {
+	if c.copyMode == DeepCopy {
+		chunkLen, err := pyChunkGetLength(pyChunk)
+		if err != nil {
+			return nil, err
+		}
+		return c.pyChunkCopyRange(pyChunk, dtype, 0, chunkLen)
+	}
+
+	// buffers() of a nested chunk also lists the buffers of its children,
+	// only the leading buffers belong to the chunk itself.
+	buffers, err := c.pyChunkGetBuffers(pyChunk, dataTypeNumBuffers(dtype))
 	if err != nil {
 		return nil, err
 	}
+	// NewData retains the buffers it is given
+	defer releaseBuffers(buffers)
 
-	nullCount, err := PyChunkGetNullCount(pyChunk)
+	nullCount, err := pyChunkGetNullCount(pyChunk)
 	if err != nil {
 		return nil, err
 	}
 
-	offset, err := PyChunkGetOffset(pyChunk)
+	offset, err := pyChunkGetOffset(pyChunk)
 	if err != nil {
 		return nil, err
 	}
 
-	chunkLen, err := PyChunkGetLength(pyChunk)
+	chunkLen, err := pyChunkGetLength(pyChunk)
 	if err != nil {
 		return nil, err
 	}
 
-	var childData []*array.Data // TODO: Implement
+	childData, err := c.pyChunkGetChildData(pyChunk, dtype)
+	if err != nil {
+		return nil, err
+	}
+	// NewData retains the child data it is given
+	defer func() {
+		for _, child := range childData {
+			child.Release()
+		}
+	}()
+
 	data := array.NewData(dtype, chunkLen, buffers, childData, nullCount, offset)
 	return data, nil
 }
 
+// PyChunkGetChildData returns the Go child data of a nested pyChunk, or nil
+// if dtype is not a nested type. The returned data must be Release()'d after use.
+func PyChunkGetChildData(pyChunk *python3.PyObject, dtype arrow.DataType) ([]*array.Data, error) {
+	checkGIL()
+
+	return defaultConverter.pyChunkGetChildData(pyChunk, dtype)
+}
+
+func (c *Converter) pyChunkGetChildData(pyChunk *python3.PyObject, dtype arrow.DataType) ([]*array.Data, error) {
+	switch dt := dtype.(type) {
+	case *arrow.ListType:
+		child, err := c.pyChunkValuesToData(pyChunk, dt.Elem())
+		if err != nil {
+			return nil, withPath(err, "values")
+		}
+		return []*array.Data{child}, nil
+
+	case *arrow.FixedSizeListType:
+		child, err := c.pyChunkValuesToData(pyChunk, dt.Elem())
+		if err != nil {
+			return nil, withPath(err, "values")
+		}
+		return []*array.Data{child}, nil
+
+	case *DictionaryType:
+		child, err := c.pyChunkDictionaryToData(pyChunk, dt)
+		if err != nil {
+			return nil, withPath(err, "dictionary")
+		}
+		return []*array.Data{child}, nil
+
+	case *arrow.StructType:
+		fields := dt.Fields()
+		childData := make([]*array.Data, 0, len(fields))
+		for i := range fields {
+			child, err := c.pyChunkFieldToData(pyChunk, i, fields[i].Type)
+			if err != nil {
+				for _, child := range childData {
+					child.Release()
+				}
+				return nil, withPath(err, "field %d %q", i, fields[i].Name)
+			}
+			childData = append(childData, child)
+		}
+		return childData, nil
+	}
+
+	return nil, nil
+}
+
+func (c *Converter) pyChunkValuesToData(pyChunk *python3.PyObject, dtype arrow.DataType) (*array.Data, error) {
+	pyValues, err := pyChunkGetPyValues(pyChunk)
+	if err != nil {
+		return nil, err
+	}
+	defer pyValues.DecRef()
+
+	return c.pyChunkToData(pyValues, dtype)
+}
+
+func (c *Converter) pyChunkFieldToData(pyChunk *python3.PyObject, i int, dtype arrow.DataType) (*array.Data, error) {
+	pyIndex := python3.PyLong_FromLong(i)
+	defer pyIndex.DecRef()
+
+	// StructArray.field slices the child to the offset and length of the
+	// chunk, which is what Go struct arrays expect.
+	pyField := CallPyFunc(pyChunk, "field", pyIndex)
+	if pyField == nil {
+		return nil, pyError("could not get pyChunk.field()")
+	}
+	defer pyField.DecRef()
+
+	return c.pyChunkToData(pyField, dtype)
+}
+
+// PyChunkGetPyValues returns the values of a list pyChunk. The values are
+// not sliced to the offset of the chunk because the list offsets index
+// into all of them.
+func PyChunkGetPyValues(pyChunk *python3.PyObject) (*python3.PyObject, error) {
+	checkGIL()
+
+	return pyChunkGetPyValues(pyChunk)
+}
+
+func pyChunkGetPyValues(pyChunk *python3.PyObject) (*python3.PyObject, error) {
+	if pyChunk.HasAttrString("values") {
+		pyValues := pyAttr(pyChunk, "values")
+		if pyValues == nil {
+			return nil, pyError("could not get pyChunk.values")
+		}
+		return pyValues, nil
+	}
+
+	// Older versions of pyarrow only have flatten, which does not slice
+	// the values either.
+	pyValues := CallPyFunc(pyChunk, "flatten")
+	if pyValues == nil {
+		return nil, pyError("could not get pyChunk.flatten()")
+	}
+	return pyValues, nil
+}
+
+// PyChunkGetBuffers returns the Go buffers of the pyChunk. The returned
+// buffers must be Release()'d after use.
 func PyChunkGetBuffers(pyChunk *python3.PyObject) ([]*memory.Buffer, error) {
-	pyBuffers, err := PyChunkGetPyBuffers(pyChunk)
+	checkGIL()
+
+	return defaultConverter.pyChunkGetBuffers(pyChunk, -1)
+}
+
+// pyChunkGetBuffers returns the first n Go buffers of the pyChunk, or all
+// of them when n is negative.
+func (c *Converter) pyChunkGetBuffers(pyChunk *python3.PyObject, n int) ([]*memory.Buffer, error) {
+	pyBuffers, err := pyChunkGetPyBuffers(pyChunk)
 	if err != nil {
 		return nil, err
 	}
 	defer pyBuffers.DecRef()
 
-	return PyBuffersToBuffers(pyBuffers)
+	return pyBuffersToBuffers(pyBuffers, n, c.foreignAccounting)
 }
 
 func PyChunkGetPyBuffers(pyChunk *python3.PyObject) (*python3.PyObject, error) {
-	pyBuffersFunc := pyChunk.GetAttrString("buffers")
+	checkGIL()
+
+	return pyChunkGetPyBuffers(pyChunk)
+}
+
+func pyChunkGetPyBuffers(pyChunk *python3.PyObject) (*python3.PyObject, error) {
+	pyBuffersFunc := pyAttr(pyChunk, "buffers")
 	if pyBuffersFunc == nil {
-		return nil, errors.New("could not get pyBuffersFunc")
+		return nil, pyError("could not get pyBuffersFunc")
 	}
 	defer pyBuffersFunc.DecRef()
 
 	pyBuffers := pyBuffersFunc.CallFunctionObjArgs()
 	if pyBuffers == nil {
-		return nil, errors.New("could not get pyBuffers")
+		return nil, pyError("could not get pyBuffers")
 	}
 
 	return pyBuffers, nil
 }
 
 func PyChunkGetNullCount(pyChunk *python3.PyObject) (int, error) {
+	checkGIL()
+
+	return pyChunkGetNullCount(pyChunk)
+}
+
+func pyChunkGetNullCount(pyChunk *python3.PyObject) (int, error) {
 	v, ok := GetIntAttr(pyChunk, "null_count")
 	if !ok {
-		return 0, errors.New("could not get null_count")
+		return 0, pyError("could not get null_count")
 	}
 	return v, nil
 }
 
 func PyChunkGetOffset(pyChunk *python3.PyObject) (int, error) {
+	checkGIL()
+
+	return pyChunkGetOffset(pyChunk)
+}
+
+func pyChunkGetOffset(pyChunk *python3.PyObject) (int, error) {
 	v, ok := GetIntAttr(pyChunk, "offset")
 	if !ok {
-		return 0, errors.New("could not get offset")
+		return 0, pyError("could not get offset")
 	}
 	return v, nil
 }
 
 func PyChunkGetLength(pyChunk *python3.PyObject) (int, error) {
+	checkGIL()
+
+	return pyChunkGetLength(pyChunk)
+}
+
+func pyChunkGetLength(pyChunk *python3.PyObject) (int, error) {
 	pyLength := CallPyFunc(pyChunk, "__len__")
 	if pyLength == nil {
-		return 0, errors.New("could not get pyChunk.__len__()")
+		return 0, pyError("could not get pyChunk.__len__()")
 	}
 	defer pyLength.DecRef()
 	length := python3.PyLong_AsLong(pyLength)
 	return length, nil
 }
+
+// ChunkedToPyChunked returns a pyarrow ChunkedArray sharing the buffers of the Go chunks.
+func ChunkedToPyChunked(chunked *array.Chunked) (*python3.PyObject, error) {
+	checkGIL()
+
+	return chunkedToPyChunked(chunked)
+}
+
+func chunkedToPyChunked(chunked *array.Chunked) (*python3.PyObject, error) {
+	pyDtype, err := dataTypeToPyDataType(chunked.DataType())
+	if err != nil {
+		return nil, err
+	}
+	defer pyDtype.DecRef()
+
+	chunks := chunked.Chunks()
+	pyChunks := make([]*python3.PyObject, 0, len(chunks))
+	defer func() {
+		for i := range pyChunks {
+			pyChunks[i].DecRef()
+		}
+	}()
+
+	for i, chunk := range chunks {
+		pyChunk, err := chunkToPyChunk(chunk, pyDtype)
+		if err != nil {
+			return nil, withPath(err, "chunk %d", i)
+		}
+		pyChunks = append(pyChunks, pyChunk)
+	}
+
+	pyChunkList := NewPyList(pyChunks)
+	defer pyChunkList.DecRef()
+
+	return callPyArrowFunc("chunked_array", pyChunkList, pyDtype)
+}
+
+// ChunkToPyChunk returns a pyarrow Array of type pyDtype sharing the buffers of the Go chunk.
+// The children of list, fixed size list and struct chunks are passed to
+// Array.from_buffers too, which older versions of pyarrow do not support.
+func ChunkToPyChunk(chunk array.Interface, pyDtype *python3.PyObject) (*python3.PyObject, error) {
+	checkGIL()
+
+	return chunkToPyChunk(chunk, pyDtype)
+}
+
+func chunkToPyChunk(chunk array.Interface, pyDtype *python3.PyObject) (*python3.PyObject, error) {
+	if dict, ok := chunk.(*Dictionary); ok {
+		return dictionaryChunkToPyChunk(dict)
+	}
+
+	data := chunk.Data()
+	buffers, offset := data.Buffers(), data.Offset()
+	if _, ok := chunk.(*array.Struct); ok && offset != 0 {
+		// The fields of a Go struct array start at its first row, while
+		// pyarrow applies the offset of the struct to its fields. Copy the
+		// validity bitmap so the struct starts at offset 0 as well.
+		if buffers[0] != nil {
+			bitmap, _ := copyBitmap(memory.DefaultAllocator, buffers[0], offset, data.Len())
+			defer bitmap.Release()
+			buffers = []*memory.Buffer{bitmap}
+		}
+		offset = 0
+	}
+
+	pyBuffers, err := buffersToPyBuffers(buffers)
+	if err != nil {
+		return nil, err
+	}
+	defer pyBuffers.DecRef()
+
+	pyArrayType, err := getPyArrowAttr("Array")
+	if err != nil {
+		return nil, err
+	}
+	defer pyArrayType.DecRef()
+
+	pyLength := python3.PyLong_FromLong(data.Len())
+	defer pyLength.DecRef()
+
+	pyNullCount := python3.PyLong_FromLong(data.NullN())
+	defer pyNullCount.DecRef()
+
+	pyOffset := python3.PyLong_FromLong(offset)
+	defer pyOffset.DecRef()
+
+	children := chunkChildren(chunk)
+	if children == nil {
+		pyChunk := CallPyFunc(pyArrayType, "from_buffers", pyDtype, pyLength, pyBuffers, pyNullCount, pyOffset)
+		if pyChunk == nil {
+			return nil, pyError("could not call pyarrow.Array.from_buffers")
+		}
+		return pyChunk, nil
+	}
+
+	pyChildren, err := chunksToPyChunks(children)
+	if err != nil {
+		return nil, err
+	}
+	defer pyChildren.DecRef()
+
+	args := []*python3.PyObject{pyDtype, pyLength, pyBuffers, pyNullCount, pyOffset}
+	pyChunk := CallPyFuncKwargs(pyArrayType, "from_buffers", args, map[string]*python3.PyObject{"children": pyChildren})
+	if pyChunk == nil {
+		return nil, pyError("could not call pyarrow.Array.from_buffers with children")
+	}
+	return pyChunk, nil
+}
+
+// chunkChildren returns the child arrays of a nested chunk, which are owned
+// by the chunk, or nil if it has none.
+func chunkChildren(chunk array.Interface) []array.Interface {
+	switch a := chunk.(type) {
+	case *array.List:
+		return []array.Interface{a.ListValues()}
+	case *array.FixedSizeList:
+		return []array.Interface{a.ListValues()}
+	case *array.Struct:
+		children := make([]array.Interface, a.NumField())
+		for i := range children {
+			children[i] = a.Field(i)
+		}
+		return children
+	}
+	return nil
+}
+
+// chunksToPyChunks returns a Python list of the pyarrow arrays sharing the
+// buffers of the Go chunks.
+func chunksToPyChunks(chunks []array.Interface) (*python3.PyObject, error) {
+	pyChunks := make([]*python3.PyObject, 0, len(chunks))
+	defer func() {
+		for i := range pyChunks {
+			pyChunks[i].DecRef()
+		}
+	}()
+
+	for i, chunk := range chunks {
+		pyDtype, err := dataTypeToPyDataType(chunk.DataType())
+		if err != nil {
+			return nil, withPath(err, "child %d", i)
+		}
+		pyChunk, err := chunkToPyChunk(chunk, pyDtype)
+		pyDtype.DecRef()
+		if err != nil {
+			return nil, withPath(err, "child %d", i)
+		}
+		pyChunks = append(pyChunks, pyChunk)
+	}
+	return NewPyList(pyChunks), nil
+}
@@ -0,0 +1,67 @@
+package bridge
+
+// #include "bridge.h"
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// batchable reports whether the chunks of dtype can be converted with
+// pyChunkedToChunksBatched, i.e. whether they have no children.
+func batchable(dtype arrow.DataType) bool {
+	switch dtype.(type) {
+	case *arrow.ListType, *arrow.FixedSizeListType, *arrow.StructType, *DictionaryType:
+		return false
+	}
+	return dataTypeNumBuffers(dtype) > 0
+}
+
+// pyChunkedToChunksBatched converts the chunks of a pyarrow ChunkedArray of
+// a type without children. The buffers, lengths, offsets and null counts of
+// all the chunks are collected by a single cgo call rather than by several
+// calls into Python per chunk.
+func (c *Converter) pyChunkedToChunksBatched(pyChunked *python3.PyObject, dtype arrow.DataType) ([]array.Interface, error) {
+	var failed C.int64_t
+	info := C.bridge_get_chunks((*C.PyObject)(unsafe.Pointer(pyChunked)), C.int64_t(dataTypeNumBuffers(dtype)), &failed)
+	if info == nil {
+		err := pyError("could not get the chunks")
+		if failed >= 0 {
+			return nil, withPath(err, "chunk %d", int(failed))
+		}
+		return nil, err
+	}
+	defer C.bridge_free_chunks(info)
+
+	n, nb := int(info.n_chunks), int(info.n_buffers)
+	if n == 0 {
+		return nil, nil
+	}
+	lengths := (*[1 << 28]C.int64_t)(unsafe.Pointer(info.lengths))[:n:n]
+	nullCounts := (*[1 << 28]C.int64_t)(unsafe.Pointer(info.null_counts))[:n:n]
+	offsets := (*[1 << 28]C.int64_t)(unsafe.Pointer(info.offsets))[:n:n]
+	views := (*[1 << 28]*C.Py_buffer)(unsafe.Pointer(info.views))[: n*nb : n*nb]
+
+	chunks := make([]array.Interface, 0, n)
+	buffers := make([]*memory.Buffer, nb)
+	for i := 0; i < n; i++ {
+		for j := range buffers {
+			buffers[j] = nil
+			if view := views[i*nb+j]; view != nil {
+				buffers[j] = newViewBuffer(view, c.foreignAccounting)
+			}
+		}
+
+		// NewData retains the buffers it is given
+		data := array.NewData(dtype, int(lengths[i]), buffers, nil, int(nullCounts[i]), int(offsets[i]))
+		releaseBuffers(buffers)
+		chunks = append(chunks, makeFromData(data))
+		data.Release()
+	}
+	return chunks, nil
+}
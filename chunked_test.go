@@ -0,0 +1,83 @@
+package bridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+// withFooResult calls fn with the object returned by the foo module
+// function pyMethod while holding the GIL.
+func withFooResult(t *testing.T, pyMethod string, fn func(pyObj *python3.PyObject) error) {
+	t.Helper()
+
+	fooModule, release := importFoo(t)
+	defer release()
+
+	runPython(t, func() error {
+		pyObj := CallPyFunc(fooModule, pyMethod)
+		if pyObj == nil {
+			return pyErrorf("could not call foo.%s", pyMethod)
+		}
+		defer pyObj.DecRef()
+		return fn(pyObj)
+	})
+}
+
+// runPython calls fn while holding the GIL and fails the test if it fails.
+func runPython(t *testing.T, fn func() error) {
+	t.Helper()
+
+	var err error
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		err = fn()
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPyArrayToArray(t *testing.T) {
+	var arr array.Interface
+	withFooResult(t, "single_array", func(pyArray *python3.PyObject) (err error) {
+		arr, err = PyArrayToArray(pyArray)
+		return err
+	})
+	defer arr.Release()
+
+	if got, want := fmt.Sprintf("%v", arr.DataType()), "int32"; got != want {
+		t.Fatalf("got type=%s, want=%s", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", arr), "[1 (null) 3]"; got != want {
+		t.Fatalf("got=%s, want=%s", got, want)
+	}
+}
+
+func TestPyChunkedArrayToChunked(t *testing.T) {
+	var chunked *array.Chunked
+	withFooResult(t, "single_chunked_array", func(pyChunked *python3.PyObject) (err error) {
+		chunked, err = PyChunkedArrayToChunked(pyChunked)
+		return err
+	})
+	defer chunked.Release()
+
+	if got, want := fmt.Sprintf("%v", chunked.DataType()), "utf8"; got != want {
+		t.Fatalf("got type=%s, want=%s", got, want)
+	}
+	if got, want := chunked.Len(), 4; got != want {
+		t.Fatalf("got len=%d, want=%d", got, want)
+	}
+
+	wantChunks := []string{`["a" (null)]`, `["c" "d"]`}
+	for i, chunk := range chunked.Chunks() {
+		if got := fmt.Sprintf("%v", chunk); got != wantChunks[i] {
+			t.Fatalf("chunk %d: got=%s, want=%s", i, got, wantChunks[i])
+		}
+	}
+}
@@ -3,8 +3,6 @@ package main
 import (
 	"fmt"
 
-	"github.com/DataDog/go-python3"
-	"github.com/apache/arrow/go/arrow/array"
 	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/go-bullseye/bullseye/dataframe"
 	bridge "github.com/nickpoorman/go-py-arrow-bridge"
@@ -12,33 +10,17 @@ import (
 )
 
 func main() {
-	py := pytasks.GetPythonSingleton()
-
-	fooModule, err := py.ImportModule("foo")
+	b, err := bridge.NewBridge(pytasks.GetPythonSingleton())
 	if err != nil {
 		panic(err)
 	}
-	defer func() {
-		err := pytasks.GetPythonSingleton().NewTaskSync(func() {
-			fooModule.DecRef()
-		})
-		if err != nil {
-			panic(err)
-		}
-	}()
 
-	var table array.Table
-	taskErr := py.NewTaskSync(func() {
-		pyTable := genPyTable(fooModule)
-		table, err = bridge.PyTableToTable(pyTable)
-		pyTable.DecRef()
-	})
-	if taskErr != nil {
-		panic(taskErr)
-	}
+	// The Bridge acquires the GIL for the call and the conversion.
+	table, err := b.ImportTable("foo", "zero_copy_chunks")
 	if err != nil {
 		panic(err)
 	}
+	defer table.Release()
 
 	// Wrapping it in a bullseye dataframe allows us to print it easily
 	pool := memory.NewGoAllocator()
@@ -67,11 +49,3 @@ func main() {
 	// rec[4]["f1"]: ["foo" "bar" "baz" (null)]
 	// rec[4]["f2"]: [true (null) false true]
 }
-
-func genPyTable(module *python3.PyObject) *python3.PyObject {
-	pyTable := bridge.CallPyFunc(module, "zero_copy_chunks")
-	if pyTable == nil {
-		panic("pyTable is nil")
-	}
-	return pyTable
-}
@@ -1,8 +1,6 @@
 package bridge
 
 import (
-	"errors"
-
 	"github.com/DataDog/go-python3"
 	"github.com/apache/arrow/go/arrow"
 	"github.com/apache/arrow/go/arrow/array"
@@ -10,29 +8,60 @@ import (
 
 // PyColumnToColumnWithField turns a PyColumn into a GoColumn
 func PyColumnToColumnWithField(pyColumn *python3.PyObject, field arrow.Field) (*array.Column, error) {
-	chunks, err := PyColumnToChunkedWithField(pyColumn, field)
+	checkGIL()
+
+	return defaultConverter.pyColumnToColumnWithField(pyColumn, field)
+}
+
+func (c *Converter) pyColumnToColumnWithField(pyColumn *python3.PyObject, field arrow.Field) (*array.Column, error) {
+	chunks, err := c.pyColumnToChunkedWithField(pyColumn, field)
 	if err != nil {
 		return nil, err
 	}
 
+	// NewColumn retains the chunks it is given
 	col := array.NewColumn(field, chunks)
+	chunks.Release()
 	return col, nil
 }
 
 func PyColumnToChunkedWithField(pyColumn *python3.PyObject, field arrow.Field) (*array.Chunked, error) {
-	pyChunked, err := PyColumnGetPyChunked(pyColumn)
+	checkGIL()
+
+	return defaultConverter.pyColumnToChunkedWithField(pyColumn, field)
+}
+
+func (c *Converter) pyColumnToChunkedWithField(pyColumn *python3.PyObject, field arrow.Field) (*array.Chunked, error) {
+	pyChunked, err := pyColumnGetPyChunked(pyColumn)
 	if err != nil {
 		return nil, err
 	}
 	defer pyChunked.DecRef()
 
-	return PyChunkedToChunked(pyChunked, field.Type)
+	return c.pyChunkedToChunked(pyChunked, field.Type)
 }
 
 func PyColumnGetPyChunked(pyColumn *python3.PyObject) (*python3.PyObject, error) {
-	pyChunked := pyColumn.GetAttrString("data")
+	checkGIL()
+
+	return pyColumnGetPyChunked(pyColumn)
+}
+
+func pyColumnGetPyChunked(pyColumn *python3.PyObject) (*python3.PyObject, error) {
+	pyChunked := pyAttr(pyColumn, "data")
 	if pyChunked == nil {
-		return nil, errors.New("could not get pyChunked")
+		return nil, pyError("could not get pyChunked")
 	}
 	return pyChunked, nil
 }
+
+// ColumnToPyChunked turns a GoColumn into a pyarrow ChunkedArray.
+func ColumnToPyChunked(col *array.Column) (*python3.PyObject, error) {
+	checkGIL()
+
+	return columnToPyChunked(col)
+}
+
+func columnToPyChunked(col *array.Column) (*python3.PyObject, error) {
+	return chunkedToPyChunked(col.Data())
+}
@@ -0,0 +1,316 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// CopyMode controls whether converted Go arrays share the memory of the
+// pyarrow arrays or own a copy of it.
+type CopyMode int
+
+const (
+	// ZeroCopy shares the pyarrow memory, which stays alive until the Go
+	// buffers are released.
+	ZeroCopy CopyMode = iota
+	// DeepCopy copies the values referenced by the pyarrow arrays, and
+	// nothing else, into buffers allocated with the allocator of the
+	// Converter. The converted arrays hold no reference to Python and stay
+	// valid after the interpreter is finalized.
+	DeepCopy
+)
+
+func (m CopyMode) String() string {
+	switch m {
+	case ZeroCopy:
+		return "zero-copy"
+	case DeepCopy:
+		return "deep-copy"
+	}
+	return fmt.Sprintf("CopyMode(%d)", int(m))
+}
+
+// UnknownTypePolicy controls what happens to columns whose type can not be
+// converted.
+type UnknownTypePolicy int
+
+const (
+	// StrictTypes fails the conversion with an *UnsupportedTypeError.
+	StrictTypes UnknownTypePolicy = iota
+	// LenientTypes drops the columns from the converted schema.
+	LenientTypes
+)
+
+func (p UnknownTypePolicy) String() string {
+	switch p {
+	case StrictTypes:
+		return "strict"
+	case LenientTypes:
+		return "lenient"
+	}
+	return fmt.Sprintf("UnknownTypePolicy(%d)", int(p))
+}
+
+// Converter converts pyarrow objects to Go arrow with the options it was
+// created with. The package level functions use a Converter with the
+// default options.
+//
+// A Converter is immutable and can be shared. It remembers the conversion
+// of the schemas it saw, see WithPlanCache. Its methods must be called with
+// the GIL held.
+type Converter struct {
+	mem            memory.Allocator
+	copyMode       CopyMode
+	unknownTypes   UnknownTypePolicy
+	dictionaryMode DictionaryMode
+	columns        []columnRef // nil converts all the columns
+
+	rows      bool // whether only rowLength rows from rowOffset are converted
+	rowOffset int
+	rowLength int
+
+	planCacheSize int
+	plans         *planCache // nil if disabled
+
+	foreignAccounting memory.Allocator // see withForeignAccounting
+}
+
+// columnRef selects a column by name, or by index when index is not -1.
+type columnRef struct {
+	name  string
+	index int
+}
+
+func (r columnRef) String() string {
+	if r.index >= 0 {
+		return fmt.Sprintf("column %d", r.index)
+	}
+	return fmt.Sprintf("column %q", r.name)
+}
+
+// ConverterOption configures a Converter.
+type ConverterOption func(c *Converter) error
+
+// NewConverter returns a Converter configured by opts. By default it does
+// zero-copy conversions, fails on unknown types, keeps dictionaries per
+// chunk, converts all the columns and caches the conversion plans of 64
+// schemas.
+func NewConverter(opts ...ConverterOption) (*Converter, error) {
+	c := &Converter{
+		mem:           memory.NewGoAllocator(),
+		planCacheSize: defaultPlanCacheSize,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.planCacheSize > 0 {
+		c.plans = newPlanCache(c.planCacheSize)
+	}
+	return c, nil
+}
+
+var defaultConverter, _ = NewConverter()
+
+// WithAllocator sets the allocator of the memory the Converter allocates
+// itself, e.g. the buffers of a DeepCopy conversion.
+func WithAllocator(mem memory.Allocator) ConverterOption {
+	return func(c *Converter) error {
+		if mem == nil {
+			return fmt.Errorf("allocator must not be nil")
+		}
+		c.mem = mem
+		return nil
+	}
+}
+
+// WithCopyMode sets whether the converted arrays share the pyarrow memory.
+func WithCopyMode(mode CopyMode) ConverterOption {
+	return func(c *Converter) error {
+		switch mode {
+		case ZeroCopy, DeepCopy:
+		default:
+			return fmt.Errorf("unknown copy mode %v", mode)
+		}
+		c.copyMode = mode
+		return nil
+	}
+}
+
+// WithUnknownTypes sets what happens to columns of unsupported types.
+func WithUnknownTypes(policy UnknownTypePolicy) ConverterOption {
+	return func(c *Converter) error {
+		switch policy {
+		case StrictTypes, LenientTypes:
+		default:
+			return fmt.Errorf("unknown type policy %v", policy)
+		}
+		c.unknownTypes = policy
+		return nil
+	}
+}
+
+// WithDictionaryMode sets how dictionary encoded columns of tables are
+// converted. The default, DictionaryKeep, converts them to *Dictionary
+// chunks which Go arrow can not slice, see DictionaryType; DictionaryDecode
+// converts them to plain arrays that support all of Go arrow.
+func WithDictionaryMode(mode DictionaryMode) ConverterOption {
+	return func(c *Converter) error {
+		switch mode {
+		case DictionaryKeep, DictionaryUnify, DictionaryDecode:
+		default:
+			return fmt.Errorf("unknown dictionary mode %v", mode)
+		}
+		c.dictionaryMode = mode
+		return nil
+	}
+}
+
+// WithColumns only converts the named columns of tables and record
+// batches, in the given order. It can be combined with WithColumnIndices,
+// the columns are then selected in the order of the options.
+func WithColumns(names ...string) ConverterOption {
+	return func(c *Converter) error {
+		for _, name := range names {
+			c.columns = append(c.columns, columnRef{name: name, index: -1})
+		}
+		return nil
+	}
+}
+
+// WithColumnIndices only converts the columns of tables and record batches
+// at the given indices, in the given order.
+func WithColumnIndices(indices ...int) ConverterOption {
+	return func(c *Converter) error {
+		for _, i := range indices {
+			if i < 0 {
+				return fmt.Errorf("negative column index %d", i)
+			}
+			c.columns = append(c.columns, columnRef{index: i})
+		}
+		return nil
+	}
+}
+
+// WithRows only converts length rows starting at offset of tables, chunked
+// arrays, arrays and record batches. The range is clipped to the rows
+// there are, a length past the last row converts every row from offset.
+// Chunks entirely outside of the range are not converted at all, the others
+// are sliced. A PyRecordReader converts every row.
+func WithRows(offset, length int) ConverterOption {
+	return func(c *Converter) error {
+		if offset < 0 || length < 0 {
+			return fmt.Errorf("invalid row range offset=%d length=%d", offset, length)
+		}
+		// The end of the range, offset+length, must not overflow.
+		if maxLength := maxInt - offset; length > maxLength {
+			length = maxLength
+		}
+		c.rows, c.rowOffset, c.rowLength = true, offset, length
+		return nil
+	}
+}
+
+// WithPlanCache sets how many pyarrow schemas the Converter remembers the
+// conversion of, the least recently used one is forgotten first. The
+// conversion of a table, record batch or reader of a known schema then skips
+// resolving the types of its fields. Schemas are identified by the
+// serialization of their fields, the schema metadata is converted every
+// time. A size of 0 disables the cache.
+func WithPlanCache(size int) ConverterOption {
+	return func(c *Converter) error {
+		if size < 0 {
+			return fmt.Errorf("invalid plan cache size %d", size)
+		}
+		c.planCacheSize = size
+		return nil
+	}
+}
+
+// withForeignAccounting accounts for the foreign buffers the Converter
+// creates in mem, see newForeignBuffer. Tests use a memory.CheckedAllocator
+// to find leaked buffers.
+func withForeignAccounting(mem memory.Allocator) ConverterOption {
+	return func(c *Converter) error {
+		c.foreignAccounting = mem
+		return nil
+	}
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// rowRange returns the rows [lo, hi) of the n rows starting at row pos that
+// are selected by c.
+func (c *Converter) rowRange(pos, n int) (lo, hi int) {
+	if !c.rows {
+		return 0, n
+	}
+	clip := func(i int) int {
+		if i < 0 {
+			return 0
+		}
+		if i > n {
+			return n
+		}
+		return i
+	}
+	return clip(c.rowOffset - pos), clip(c.rowOffset + c.rowLength - pos)
+}
+
+// pySliceRows returns a new reference to pyObj sliced to the rows selected
+// by c, pyObj being an Array or a RecordBatch of n rows.
+func (c *Converter) pySliceRows(pyObj *python3.PyObject, n int) (*python3.PyObject, error) {
+	return c.pySliceRowsAt(pyObj, 0, n)
+}
+
+// pySliceRowsAt is pySliceRows for the chunk of n rows starting at row pos.
+func (c *Converter) pySliceRowsAt(pyObj *python3.PyObject, pos, n int) (*python3.PyObject, error) {
+	lo, hi := c.rowRange(pos, n)
+	if lo == 0 && hi == n {
+		pyObj.IncRef()
+		return pyObj, nil
+	}
+
+	pyOffset := python3.PyLong_FromLong(lo)
+	defer pyOffset.DecRef()
+	pyLength := python3.PyLong_FromLong(hi - lo)
+	defer pyLength.DecRef()
+
+	pySlice := CallPyFunc(pyObj, "slice", pyOffset, pyLength)
+	if pySlice == nil {
+		return nil, pyError("could not slice rows")
+	}
+	return pySlice, nil
+}
+
+// wholeBatch reports whether the columns at indices of a record batch with
+// numCols columns are the whole batch, in order, and can be shared, which
+// the Arrow C data interface requires.
+func (c *Converter) wholeBatch(indices []int, numCols int) bool {
+	if c.copyMode != ZeroCopy || len(indices) != numCols {
+		return false
+	}
+	for i, idx := range indices {
+		if i != idx {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnsupportedType reports whether err was caused by an unsupported type.
+func isUnsupportedType(err error) bool {
+	for {
+		switch e := err.(type) {
+		case *UnsupportedTypeError:
+			return true
+		case *ConversionError:
+			err = e.Err
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package bridge
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestConverterOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opt  ConverterOption
+		want string
+	}{
+		{"allocator", WithAllocator(nil), "allocator must not be nil"},
+		{"copy mode", WithCopyMode(CopyMode(7)), "unknown copy mode CopyMode(7)"},
+		{"unknown types", WithUnknownTypes(UnknownTypePolicy(7)), "unknown type policy UnknownTypePolicy(7)"},
+		{"dictionary mode", WithDictionaryMode(DictionaryMode(7)), "unknown dictionary mode DictionaryMode(7)"},
+		{"column indices", WithColumnIndices(1, -1), "negative column index -1"},
+		{"rows", WithRows(-1, 2), "invalid row range offset=-1 length=2"},
+		{"plan cache", WithPlanCache(-1), "invalid plan cache size -1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewConverter(tc.opt)
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("got error=%v, want=%s", err, tc.want)
+			}
+		})
+	}
+}
+
+// tableNames returns the names of the columns of table.
+func tableNames(table array.Table) []string {
+	names := make([]string, 0, table.NumCols())
+	for i := 0; i < int(table.NumCols()); i++ {
+		names = append(names, table.Column(i).Name())
+	}
+	return names
+}
+
+func TestConverterColumns(t *testing.T) {
+	conv, err := NewConverter(WithColumns("string", "int8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var table array.Table
+		withFooResult(t, "primitive_types", func(pyTable *python3.PyObject) (err error) {
+			table, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer table.Release()
+
+		if got, want := fmt.Sprintf("%v", tableNames(table)), "[string int8]"; got != want {
+			t.Fatalf("got columns=%s, want=%s", got, want)
+		}
+		testColumn(t, table, 0, "utf8", []string{`[(null)]`})
+		testColumn(t, table, 1, "int8", []string{"[(null)]"})
+	})
+
+	t.Run("record batch", func(t *testing.T) {
+		conv, err := NewConverter(WithColumns("strings"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var rec array.Record
+		withFooResult(t, "record_batch", func(pyBatch *python3.PyObject) (err error) {
+			rec, err = conv.PyRecordBatchToRecord(pyBatch)
+			return err
+		})
+		defer rec.Release()
+
+		if got, want := rec.NumCols(), int64(1); got != want {
+			t.Fatalf("got=%d columns, want=%d", got, want)
+		}
+		if got, want := fmt.Sprintf("%v", rec.Column(0)), `["a" "b" (null)]`; got != want {
+			t.Fatalf("got=%s, want=%s", got, want)
+		}
+	})
+
+	t.Run("indices", func(t *testing.T) {
+		conv, err := NewConverter(WithColumnIndices(3), WithColumns("null"), WithColumnIndices(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var table array.Table
+		withFooResult(t, "primitive_types", func(pyTable *python3.PyObject) (err error) {
+			table, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer table.Release()
+
+		if got, want := fmt.Sprintf("%v", tableNames(table)), "[int8 null bool]"; got != want {
+			t.Fatalf("got columns=%s, want=%s", got, want)
+		}
+	})
+
+	for _, tc := range []struct {
+		opt  ConverterOption
+		want string
+	}{
+		{WithColumns("missing"), `column "missing" not found`},
+		{WithColumnIndices(100), `column 100 not found`},
+	} {
+		t.Run(tc.want, func(t *testing.T) {
+			conv, err := NewConverter(tc.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var convErr error
+			withFooResult(t, "primitive_types", func(pyTable *python3.PyObject) error {
+				table, err := conv.PyTableToTable(pyTable)
+				if err == nil {
+					table.Release()
+				}
+				convErr = err
+				return nil
+			})
+			if convErr == nil || convErr.Error() != tc.want {
+				t.Fatalf("got error=%v, want=%s", convErr, tc.want)
+			}
+		})
+	}
+}
+
+func TestConverterPlanCache(t *testing.T) {
+	convert := func(t *testing.T, conv *Converter, method string, args ...interface{}) *arrow.Schema {
+		t.Helper()
+		var table array.Table
+		err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+			pyTable, err := CallModuleFunc("foo", method, args...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer pyTable.DecRef()
+			if table, err = conv.PyTableToTable(pyTable); err != nil {
+				t.Error(err)
+			}
+		})
+		if err != nil || t.Failed() {
+			t.Fatal(err)
+		}
+		defer table.Release()
+		return table.Schema()
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		conv, err := NewConverter()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema := convert(t, conv, "primitive_types")
+		if got := convert(t, conv, "primitive_types"); !got.Equal(schema) {
+			t.Fatalf("got schema=%v, want=%v", got, schema)
+		}
+		if got := conv.plans.len(); got != 1 {
+			t.Fatalf("got=%d plans, want=1", got)
+		}
+		convert(t, conv, "zero_copy_chunks")
+		if got := conv.plans.len(); got != 2 {
+			t.Fatalf("got=%d plans, want=2", got)
+		}
+	})
+
+	t.Run("schema metadata", func(t *testing.T) {
+		conv, err := NewConverter()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The tables share a plan, but keep their own metadata.
+		short, long := convert(t, conv, "pandas_table", 2), convert(t, conv, "pandas_table", 3)
+		if got := conv.plans.len(); got != 1 {
+			t.Fatalf("got=%d plans, want=1", got)
+		}
+		if reflect.DeepEqual(short.Metadata().Values(), long.Metadata().Values()) {
+			t.Fatalf("got the same metadata %v for tables of different lengths", short.Metadata())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		conv, err := NewConverter(WithPlanCache(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema := convert(t, conv, "primitive_types")
+		if conv.plans != nil {
+			t.Fatal("got a plan cache")
+		}
+		if !convert(t, conv, "primitive_types").Equal(schema) {
+			t.Fatal("got a different schema")
+		}
+	})
+}
+
+func TestPlanCacheEviction(t *testing.T) {
+	pc := newPlanCache(2)
+	a, b, c := &conversionPlan{}, &conversionPlan{}, &conversionPlan{}
+	pc.put("a", a)
+	pc.put("b", b)
+	if pc.get("a") != a {
+		t.Fatal("plan a not found")
+	}
+	// b is now the least recently used.
+	pc.put("c", c)
+	if pc.get("b") != nil {
+		t.Fatal("plan b was not evicted")
+	}
+	if pc.get("a") != a || pc.get("c") != c {
+		t.Fatal("plans a and c were evicted")
+	}
+	if got := pc.len(); got != 2 {
+		t.Fatalf("got=%d plans, want=2", got)
+	}
+}
+
+func TestConverterRows(t *testing.T) {
+	for _, mode := range []CopyMode{ZeroCopy, DeepCopy} {
+		t.Run(mode.String(), func(t *testing.T) {
+			// zero_copy_chunks has 5 chunks of 4 rows, rows 6 to 10 are
+			// the end of the second chunk and the start of the third.
+			conv, err := NewConverter(WithRows(6, 5), WithCopyMode(mode))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var table array.Table
+			withFooResult(t, "zero_copy_chunks", func(pyTable *python3.PyObject) (err error) {
+				table, err = conv.PyTableToTable(pyTable)
+				return err
+			})
+			defer table.Release()
+
+			if got, want := table.NumRows(), int64(5); got != want {
+				t.Fatalf("got=%d rows, want=%d", got, want)
+			}
+			testColumn(t, table, 0, "int64", []string{"[3 4]", "[1 2 3]"})
+			testColumn(t, table, 1, "utf8", []string{`["baz" (null)]`, `["foo" "bar" "baz"]`})
+			testColumn(t, table, 2, "bool", []string{"[false true]", "[true (null) false]"})
+		})
+	}
+
+	t.Run("outside", func(t *testing.T) {
+		conv, err := NewConverter(WithRows(100, 5))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var table array.Table
+		withFooResult(t, "zero_copy_chunks", func(pyTable *python3.PyObject) (err error) {
+			table, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer table.Release()
+
+		if got, want := table.NumRows(), int64(0); got != want {
+			t.Fatalf("got=%d rows, want=%d", got, want)
+		}
+		testColumn(t, table, 0, "int64", nil)
+	})
+
+	t.Run("to the end", func(t *testing.T) {
+		conv, err := NewConverter(WithRows(10, maxInt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lo, hi := conv.rowRange(4, 8); lo != 6 || hi != 8 {
+			t.Fatalf("got rows [%d, %d), want [6, 8)", lo, hi)
+		}
+
+		var table array.Table
+		withFooResult(t, "zero_copy_chunks", func(pyTable *python3.PyObject) (err error) {
+			table, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer table.Release()
+
+		if got, want := table.NumRows(), int64(10); got != want {
+			t.Fatalf("got=%d rows, want=%d", got, want)
+		}
+	})
+
+	t.Run("record batch", func(t *testing.T) {
+		conv, err := NewConverter(WithRows(1, 5))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var rec array.Record
+		withFooResult(t, "record_batch", func(pyBatch *python3.PyObject) (err error) {
+			rec, err = conv.PyRecordBatchToRecord(pyBatch)
+			return err
+		})
+		defer rec.Release()
+
+		if got, want := fmt.Sprintf("%v", rec.Column(0)), "[(null) 3]"; got != want {
+			t.Fatalf("got=%s, want=%s", got, want)
+		}
+	})
+}
+
+func TestConverterLenientTypes(t *testing.T) {
+	conv, err := NewConverter(WithUnknownTypes(LenientTypes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var table array.Table
+	skip := false
+	withFooResult(t, "unsupported_type", func(pyTable *python3.PyObject) (err error) {
+		if pyTable == python3.Py_None {
+			skip = true
+			return nil
+		}
+		table, err = conv.PyTableToTable(pyTable)
+		return err
+	})
+	if skip {
+		t.Skip("pyarrow has no large_string type")
+	}
+	defer table.Release()
+
+	if got, want := fmt.Sprintf("%v", tableNames(table)), "[ok]"; got != want {
+		t.Fatalf("got columns=%s, want=%s", got, want)
+	}
+	testColumn(t, table, 0, "int64", []string{"[1]"})
+}
+
+// testMapColumn compares the entries of the converted map columns.
+func testMapColumn(t *testing.T, col, want *array.Column) {
+	t.Helper()
+	chunks, wantChunks := col.Data().Chunks(), want.Data().Chunks()
+	if len(chunks) != len(wantChunks) {
+		t.Fatalf("got=%d chunks, want=%d", len(chunks), len(wantChunks))
+	}
+	for i := range chunks {
+		if got, want := mapEntries(chunks[i].(*array.List)), mapEntries(wantChunks[i].(*array.List)); got != want {
+			t.Fatalf("chunk %d: got=%s, want=%s", i, got, want)
+		}
+	}
+}
+
+func TestConverterDeepCopy(t *testing.T) {
+	for _, method := range []string{"nested_types", "sliced_types"} {
+		t.Run(method, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			accounting, checkForeign := checkForeignBuffers(t)
+			conv, err := NewConverter(WithCopyMode(DeepCopy), WithAllocator(mem), accounting)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var table array.Table
+			withFooResult(t, method, func(pyTable *python3.PyObject) (err error) {
+				table, err = conv.PyTableToTable(pyTable)
+				return err
+			})
+			// The Python buffers are released during the conversion, the
+			// table only holds buffers from mem.
+			checkForeign()
+			defer table.Release()
+
+			want := pyTableFromFoo(t, method)
+			defer want.Release()
+			for i := 0; i < int(want.NumCols()); i++ {
+				if want.Column(i).Name() == "maps" {
+					testMapColumn(t, table.Column(i), want.Column(i))
+				} else {
+					testColumn(t, table, i, fmt.Sprintf("%v", want.Column(i).DataType()), columnStrings(want.Column(i)))
+				}
+				for _, chunk := range table.Column(i).Data().Chunks() {
+					if got := chunk.Data().Offset(); got != 0 {
+						t.Fatalf("column %d: got offset=%d, want=0", i, got)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("referenced slice only", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		conv, err := NewConverter(WithCopyMode(DeepCopy), WithAllocator(mem))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var table array.Table
+		withFooResult(t, "large_slice", func(pyTable *python3.PyObject) (err error) {
+			table, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer table.Release()
+
+		// 3 int64 values, rounded up to 64 bytes, rather than 800000 bytes.
+		mem.AssertSize(t, 64)
+		testColumn(t, table, 0, "int64", []string{"[500 501 502]"})
+	})
+}
@@ -0,0 +1,203 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// pyChunkCopyRange copies length values of pyChunk, starting at its logical
+// index start, into memory from the allocator of c. Only the values in the
+// range are copied, the returned data starts at offset 0 and holds no
+// reference to Python memory.
+func (c *Converter) pyChunkCopyRange(pyChunk *python3.PyObject, dtype arrow.DataType, start, length int) (*array.Data, error) {
+	chunkOffset, err := pyChunkGetOffset(pyChunk)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Python buffers are only shared while they are copied.
+	src, err := c.pyChunkGetBuffers(pyChunk, dataTypeNumBuffers(dtype))
+	if err != nil {
+		return nil, err
+	}
+	defer releaseBuffers(src)
+
+	// offset indexes the buffers of pyChunk, it includes the offset of the
+	// chunk itself.
+	offset := chunkOffset + start
+
+	buffers := make([]*memory.Buffer, len(src))
+	var childData []*array.Data
+	// NewData retains the buffers and child data it is given
+	defer func() {
+		releaseBuffers(buffers)
+		for _, child := range childData {
+			child.Release()
+		}
+	}()
+
+	nullCount := 0
+	if len(src) > 0 && src[0] != nil {
+		buffers[0], nullCount = copyBitmap(c.mem, src[0], offset, length)
+	}
+
+	switch dt := dtype.(type) {
+	case *arrow.NullType:
+		nullCount = length
+
+	case *arrow.BooleanType:
+		buffers[1], _ = copyBitmap(c.mem, src[1], offset, length)
+
+	case *arrow.BinaryType, *arrow.StringType:
+		var first, last int
+		buffers[1], first, last = copyOffsets(c.mem, src[1], offset, length)
+		buffers[2] = copyBytes(c.mem, src[2], first, last-first)
+
+	case *arrow.ListType:
+		var first, last int
+		buffers[1], first, last = copyOffsets(c.mem, src[1], offset, length)
+		child, err := c.pyChunkCopyValues(pyChunk, dt.Elem(), first, last-first)
+		if err != nil {
+			return nil, withPath(err, "values")
+		}
+		childData = append(childData, child)
+
+	case *arrow.FixedSizeListType:
+		n := int(dt.Len())
+		child, err := c.pyChunkCopyValues(pyChunk, dt.Elem(), offset*n, length*n)
+		if err != nil {
+			return nil, withPath(err, "values")
+		}
+		childData = append(childData, child)
+
+	case *arrow.StructType:
+		fields := dt.Fields()
+		for i := range fields {
+			child, err := c.pyChunkCopyField(pyChunk, i, fields[i].Type, start, length)
+			if err != nil {
+				return nil, withPath(err, "field %d %q", i, fields[i].Name)
+			}
+			childData = append(childData, child)
+		}
+
+	case *DictionaryType:
+		width := dt.BitWidth() / 8
+		buffers[1] = copyBytes(c.mem, src[1], offset*width, length*width)
+		// The indices may refer to any value, the whole dictionary is copied.
+		child, err := c.pyChunkDictionaryToData(pyChunk, dt)
+		if err != nil {
+			return nil, withPath(err, "dictionary")
+		}
+		childData = append(childData, child)
+
+	case *arrow.Decimal128Type:
+		// Decimal128Type.BitWidth reports bytes rather than bits.
+		buffers[1] = copyBytes(c.mem, src[1], offset*16, length*16)
+
+	case arrow.FixedWidthDataType:
+		width := dt.BitWidth() / 8
+		buffers[1] = copyBytes(c.mem, src[1], offset*width, length*width)
+
+	default:
+		return nil, &UnsupportedTypeError{Type: fmt.Sprintf("%v", dtype)}
+	}
+
+	return array.NewData(dtype, length, buffers, childData, nullCount, 0), nil
+}
+
+// pyChunkCopyValues copies length of the values of a list pyChunk starting
+// at start.
+func (c *Converter) pyChunkCopyValues(pyChunk *python3.PyObject, dtype arrow.DataType, start, length int) (*array.Data, error) {
+	pyValues, err := pyChunkGetPyValues(pyChunk)
+	if err != nil {
+		return nil, err
+	}
+	defer pyValues.DecRef()
+
+	return c.pyChunkCopyRange(pyValues, dtype, start, length)
+}
+
+// pyChunkCopyField copies length values of the field i of a struct pyChunk
+// starting at start. StructArray.field is sliced to the chunk, so start is
+// relative to the chunk as well.
+func (c *Converter) pyChunkCopyField(pyChunk *python3.PyObject, i int, dtype arrow.DataType, start, length int) (*array.Data, error) {
+	pyIndex := python3.PyLong_FromLong(i)
+	defer pyIndex.DecRef()
+
+	pyField := CallPyFunc(pyChunk, "field", pyIndex)
+	if pyField == nil {
+		return nil, pyError("could not get pyChunk.field()")
+	}
+	defer pyField.DecRef()
+
+	return c.pyChunkCopyRange(pyField, dtype, start, length)
+}
+
+// newBuffer returns a buffer of n bytes allocated with mem.
+func newBuffer(mem memory.Allocator, n int) *memory.Buffer {
+	buf := memory.NewResizableBuffer(mem)
+	buf.Resize(n)
+	return buf
+}
+
+// bufferBytes returns the bytes of buf, or nil if buf is nil, e.g. the
+// data buffer of an empty pyarrow string array.
+func bufferBytes(buf *memory.Buffer) []byte {
+	if buf == nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// copyBytes copies n bytes of src starting at start.
+func copyBytes(mem memory.Allocator, src *memory.Buffer, start, n int) *memory.Buffer {
+	buf := newBuffer(mem, n)
+	if n > 0 {
+		copy(buf.Bytes(), bufferBytes(src)[start:start+n])
+	}
+	return buf
+}
+
+// copyBitmap copies length bits of src starting at bit offset to the start
+// of a new bitmap, and returns the number of unset bits.
+func copyBitmap(mem memory.Allocator, src *memory.Buffer, offset, length int) (*memory.Buffer, int) {
+	buf := newBuffer(mem, (length+7)/8)
+	srcBits, dstBits := bufferBytes(src), buf.Bytes()
+	memory.Set(dstBits, 0)
+
+	unset := 0
+	for i := 0; i < length; i++ {
+		j := offset + i
+		if srcBits[j/8]&(1<<uint(j%8)) != 0 {
+			dstBits[i/8] |= 1 << uint(i%8)
+		} else {
+			unset++
+		}
+	}
+	return buf, unset
+}
+
+// copyOffsets copies the length+1 int32 offsets of src starting at offset,
+// rebased to start at 0. It returns the first and last of the original
+// offsets, which bound the referenced values.
+func copyOffsets(mem memory.Allocator, src *memory.Buffer, offset, length int) (buf *memory.Buffer, first, last int) {
+	buf = newBuffer(mem, (length+1)*arrow.Int32SizeBytes)
+	dst := arrow.Int32Traits.CastFromBytes(buf.Bytes())
+
+	// pyarrow may omit the offsets of an empty array.
+	srcBytes := bufferBytes(src)
+	if len(srcBytes) == 0 {
+		dst[0] = 0
+		return buf, 0, 0
+	}
+
+	offsets := arrow.Int32Traits.CastFromBytes(srcBytes)[offset : offset+length+1]
+	for i, o := range offsets {
+		dst[i] = o - offsets[0]
+	}
+	return buf, int(offsets[0]), int(offsets[length])
+}
@@ -1,7 +1,6 @@
 package bridge
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/DataDog/go-python3"
@@ -10,20 +9,441 @@ import (
 
 // PyDataTypeToDataType returns the Go arrow DataType given the Python type.
 func PyDataTypeToDataType(pyDtype *python3.PyObject) (arrow.DataType, error) {
-	// get the id
-	id, err := PyDataTypeGetID(pyDtype)
+	checkGIL()
+
+	return pyDataTypeToDataType(pyDtype)
+}
+
+func pyDataTypeToDataType(pyDtype *python3.PyObject) (arrow.DataType, error) {
+	t, err := pyDataTypeGetType(pyDtype)
 	if err != nil {
 		return nil, err
 	}
 
-	t := arrow.Type(id)
+	switch t {
+	case arrow.LIST, arrow.FIXED_SIZE_LIST, arrow.STRUCT, arrow.MAP:
+		dtype, err := pyNestedDataTypeToDataType(pyDtype, t)
+		if err != nil {
+			return nil, err
+		}
+		if hasDictionaryChild(dtype) {
+			return nil, &UnsupportedTypeError{Type: PyObjectString(pyDtype), Python: true}
+		}
+		return dtype, nil
+	case arrow.TIMESTAMP, arrow.TIME32, arrow.TIME64, arrow.DURATION:
+		return pyTemporalDataTypeToDataType(pyDtype, t)
+	case arrow.DICTIONARY:
+		return pyDictionaryDataTypeToDataType(pyDtype)
+	case arrow.DECIMAL:
+		precision, ok := GetIntAttr(pyDtype, "precision")
+		if !ok {
+			return nil, pyError("could not get pyDtype.precision")
+		}
+		scale, ok := GetIntAttr(pyDtype, "scale")
+		if !ok {
+			return nil, pyError("could not get pyDtype.scale")
+		}
+		return &arrow.Decimal128Type{Precision: int32(precision), Scale: int32(scale)}, nil
+	case arrow.FIXED_SIZE_BINARY:
+		byteWidth, ok := GetIntAttr(pyDtype, "byte_width")
+		if !ok {
+			return nil, pyError("could not get pyDtype.byte_width")
+		}
+		return &arrow.FixedSizeBinaryType{ByteWidth: byteWidth}, nil
+	}
 	return GetFromType(t)
 }
 
+// PyObjectGetDataType returns the Go arrow DataType of the type attribute of
+// a pyarrow Array, ChunkedArray or Field.
+func PyObjectGetDataType(pyObj *python3.PyObject) (arrow.DataType, error) {
+	checkGIL()
+
+	return pyObjectGetDataType(pyObj)
+}
+
+func pyObjectGetDataType(pyObj *python3.PyObject) (arrow.DataType, error) {
+	pyDtype := pyAttr(pyObj, "type")
+	if pyDtype == nil {
+		return nil, pyError("could not get pyDtype")
+	}
+	defer pyDtype.DecRef()
+
+	return pyDataTypeToDataType(pyDtype)
+}
+
+// pyTemporalDataTypeToDataType builds the Go arrow DataType of a Python
+// temporal type from its unit and, for timestamps, its time zone.
+func pyTemporalDataTypeToDataType(pyDtype *python3.PyObject, t arrow.Type) (arrow.DataType, error) {
+	unit, err := pyDataTypeGetUnit(pyDtype)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case arrow.TIMESTAMP:
+		tz, err := pyDataTypeGetTimeZone(pyDtype)
+		if err != nil {
+			return nil, err
+		}
+		return &arrow.TimestampType{Unit: unit, TimeZone: tz}, nil
+
+	case arrow.TIME32:
+		if unit != arrow.Second && unit != arrow.Millisecond {
+			return nil, fmt.Errorf("invalid unit=%v for time32", unit)
+		}
+		return &arrow.Time32Type{Unit: unit}, nil
+
+	case arrow.TIME64:
+		if unit != arrow.Microsecond && unit != arrow.Nanosecond {
+			return nil, fmt.Errorf("invalid unit=%v for time64", unit)
+		}
+		return &arrow.Time64Type{Unit: unit}, nil
+
+	case arrow.DURATION:
+		return &arrow.DurationType{Unit: unit}, nil
+	}
+
+	return nil, fmt.Errorf("DataType for id=%v is not a temporal type", t)
+}
+
+var timeUnitForName = map[string]arrow.TimeUnit{
+	"s":  arrow.Second,
+	"ms": arrow.Millisecond,
+	"us": arrow.Microsecond,
+	"ns": arrow.Nanosecond,
+}
+
+// PyDataTypeGetUnit returns the time unit of a Python temporal type.
+func PyDataTypeGetUnit(pyDtype *python3.PyObject) (arrow.TimeUnit, error) {
+	checkGIL()
+
+	return pyDataTypeGetUnit(pyDtype)
+}
+
+func pyDataTypeGetUnit(pyDtype *python3.PyObject) (arrow.TimeUnit, error) {
+	pyUnit := pyAttr(pyDtype, "unit")
+	if pyUnit == nil {
+		return 0, pyError("could not get pyDtype.unit")
+	}
+	defer pyUnit.DecRef()
+
+	name, err := pyStringValue(pyUnit, "pyDtype.unit")
+	if err != nil {
+		return 0, err
+	}
+	unit, ok := timeUnitForName[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown time unit %q", name)
+	}
+	return unit, nil
+}
+
+// PyDataTypeGetTimeZone returns the time zone of a Python timestamp type,
+// or "" if the timestamp is time zone naive.
+func PyDataTypeGetTimeZone(pyDtype *python3.PyObject) (string, error) {
+	checkGIL()
+
+	return pyDataTypeGetTimeZone(pyDtype)
+}
+
+func pyDataTypeGetTimeZone(pyDtype *python3.PyObject) (string, error) {
+	pyTz := pyAttr(pyDtype, "tz")
+	if pyTz == nil {
+		return "", pyError("could not get pyDtype.tz")
+	}
+	defer pyTz.DecRef()
+
+	if pyTz == python3.Py_None {
+		return "", nil
+	}
+	return pyStringValue(pyTz, "pyDtype.tz")
+}
+
+// pyStringValue returns the value of the Python str pyStr, what names it in
+// the errors.
+func pyStringValue(pyStr *python3.PyObject, what string) (string, error) {
+	if !python3.PyUnicode_Check(pyStr) {
+		return "", fmt.Errorf("%s is not a str: %s", what, PyObjectString(pyStr))
+	}
+	s := python3.PyUnicode_AsUTF8(pyStr)
+	if python3.PyErr_Occurred() != nil {
+		return "", pyErrorf("could not convert %s", what)
+	}
+	return s, nil
+}
+
+// dataTypeNumBuffers returns the number of buffers in the layout of dtype,
+// not counting the buffers of any children, or -1 if it is not known.
+func dataTypeNumBuffers(dtype arrow.DataType) int {
+	switch dtype.(type) {
+	case *arrow.NullType, *arrow.FixedSizeListType, *arrow.StructType:
+		return 1
+	case *arrow.BinaryType, *arrow.StringType:
+		return 3
+	case *arrow.ListType, *DictionaryType, arrow.FixedWidthDataType:
+		return 2
+	}
+	return -1
+}
+
+// pyNestedDataTypeToDataType builds the parameterized Go arrow DataType of a
+// nested Python type by converting its child types.
+func pyNestedDataTypeToDataType(pyDtype *python3.PyObject, t arrow.Type) (arrow.DataType, error) {
+	switch t {
+	case arrow.LIST:
+		elem, err := pyDataTypeAttrToDataType(pyDtype, "value_type")
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elem), nil
+
+	case arrow.FIXED_SIZE_LIST:
+		n, ok := GetIntAttr(pyDtype, "list_size")
+		if !ok {
+			return nil, pyError("could not get pyDtype.list_size")
+		}
+		elem, err := pyDataTypeAttrToDataType(pyDtype, "value_type")
+		if err != nil {
+			return nil, err
+		}
+		return arrow.FixedSizeListOf(int32(n), elem), nil
+
+	case arrow.STRUCT:
+		fields, err := pyStructTypeGetFields(pyDtype)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkUniqueNames(fields, nil); err != nil {
+			return nil, err
+		}
+		return arrow.StructOf(fields...), nil
+
+	case arrow.MAP:
+		// Go arrow has no map type. A map is laid out as a list of
+		// key/value structs so that is what it becomes in Go.
+		key, err := pyDataTypeAttrToDataType(pyDtype, "key_type")
+		if err != nil {
+			return nil, err
+		}
+		item, err := pyDataTypeAttrToDataType(pyDtype, "item_type")
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "key", Type: key},
+			arrow.Field{Name: "value", Type: item, Nullable: true},
+		)), nil
+	}
+
+	return nil, fmt.Errorf("DataType for id=%v is not a nested type", t)
+}
+
+// pyDataTypeAttrToDataType converts the Python type held by the attribute
+// attr of pyDtype.
+func pyDataTypeAttrToDataType(pyDtype *python3.PyObject, attr string) (arrow.DataType, error) {
+	pyChildDtype := pyDtype.GetAttrString(attr)
+	if pyChildDtype == nil {
+		return nil, pyErrorf("could not get pyDtype.%s", attr)
+	}
+	defer pyChildDtype.DecRef()
+
+	return pyDataTypeToDataType(pyChildDtype)
+}
+
+// pyStructTypeGetFields returns the Go fields of a Python struct type.
+func pyStructTypeGetFields(pyDtype *python3.PyObject) ([]arrow.Field, error) {
+	numChildren, ok := GetIntAttr(pyDtype, "num_children")
+	if !ok {
+		return nil, pyError("could not get pyDtype.num_children")
+	}
+
+	fields := make([]arrow.Field, 0, numChildren)
+	for i := 0; i < numChildren; i++ {
+		pyIndex := python3.PyLong_FromLong(i)
+		pyField := pyDtype.GetItem(pyIndex)
+		pyIndex.DecRef()
+		if pyField == nil {
+			return nil, pyError("could not get pyField")
+		}
+
+		field, err := pyFieldToField(pyField)
+		pyField.DecRef()
+		if err != nil {
+			return nil, withPath(err, "field %d", i)
+		}
+		fields = append(fields, *field)
+	}
+	return fields, nil
+}
+
+// pyUnsupportedTypePredicates recognize the pyarrow types that would
+// otherwise be mistaken for a type they derive from, e.g. decimal256 is also
+// a decimal and a fixed size binary. They are checked first.
+var pyUnsupportedTypePredicates = []string{
+	"is_decimal256",
+	"is_decimal32",
+	"is_decimal64",
+}
+
+// pyTypePredicateFallbacks are the predicates used in place of the ones
+// older versions of pyarrow do not have. is_decimal is also true for the
+// other decimal widths of newer versions.
+var pyTypePredicateFallbacks = map[string]string{
+	"is_decimal128": "is_decimal",
+}
+
+// pyTypePredicates maps the pyarrow.types predicates to the Go arrow type
+// they identify. The order matters: pyarrow decimals are also fixed size
+// binaries so they have to be recognized first.
+var pyTypePredicates = []struct {
+	name string
+	t    arrow.Type
+}{
+	{"is_null", arrow.NULL},
+	{"is_boolean", arrow.BOOL},
+	{"is_uint8", arrow.UINT8},
+	{"is_int8", arrow.INT8},
+	{"is_uint16", arrow.UINT16},
+	{"is_int16", arrow.INT16},
+	{"is_uint32", arrow.UINT32},
+	{"is_int32", arrow.INT32},
+	{"is_uint64", arrow.UINT64},
+	{"is_int64", arrow.INT64},
+	{"is_float16", arrow.FLOAT16},
+	{"is_float32", arrow.FLOAT32},
+	{"is_float64", arrow.FLOAT64},
+	{"is_string", arrow.STRING},
+	{"is_binary", arrow.BINARY},
+	{"is_decimal128", arrow.DECIMAL},
+	{"is_fixed_size_binary", arrow.FIXED_SIZE_BINARY},
+	{"is_date32", arrow.DATE32},
+	{"is_date64", arrow.DATE64},
+	{"is_timestamp", arrow.TIMESTAMP},
+	{"is_time32", arrow.TIME32},
+	{"is_time64", arrow.TIME64},
+	{"is_duration", arrow.DURATION},
+	{"is_list", arrow.LIST},
+	{"is_fixed_size_list", arrow.FIXED_SIZE_LIST},
+	{"is_struct", arrow.STRUCT},
+	{"is_map", arrow.MAP},
+	{"is_dictionary", arrow.DICTIONARY},
+}
+
+// PyDataTypeGetType identifies the Go arrow.Type of the Python type with the
+// pyarrow.types predicates. Unlike the id of the Python type it does not
+// depend on pyarrow and Go arrow enumerating the types in the same order.
+func PyDataTypeGetType(pyDtype *python3.PyObject) (arrow.Type, error) {
+	checkGIL()
+
+	return pyDataTypeGetType(pyDtype)
+}
+
+func pyDataTypeGetType(pyDtype *python3.PyObject) (arrow.Type, error) {
+	if cache := loadPyCache(); cache.pyTypes != nil {
+		return pyDataTypeGetTypeCached(cache.pyTypes, pyDtype)
+	}
+
+	pyTypes, err := getPyArrowAttr("types")
+	if err != nil {
+		return 0, err
+	}
+	defer pyTypes.DecRef()
+
+	for _, name := range pyUnsupportedTypePredicates {
+		ok, err := pyTypesIs(pyTypes, name, pyDtype)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return 0, &UnsupportedTypeError{Type: PyObjectString(pyDtype), Python: true}
+		}
+	}
+
+	for _, p := range pyTypePredicates {
+		ok, err := pyTypesIs(pyTypes, p.name, pyDtype)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return p.t, nil
+		}
+	}
+
+	return 0, &UnsupportedTypeError{Type: PyObjectString(pyDtype), Python: true}
+}
+
+// pyDataTypeGetTypeCached is PyDataTypeGetType with the predicates of the
+// cache, a missing predicate is nil.
+func pyDataTypeGetTypeCached(pyPredicates map[string]*python3.PyObject, pyDtype *python3.PyObject) (arrow.Type, error) {
+	for _, name := range pyUnsupportedTypePredicates {
+		ok, err := callPyTypePredicate(pyPredicates[name], name, pyDtype)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return 0, &UnsupportedTypeError{Type: PyObjectString(pyDtype), Python: true}
+		}
+	}
+
+	for _, p := range pyTypePredicates {
+		ok, err := callPyTypePredicate(pyPredicates[p.name], p.name, pyDtype)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return p.t, nil
+		}
+	}
+
+	return 0, &UnsupportedTypeError{Type: PyObjectString(pyDtype), Python: true}
+}
+
+// pyTypesIs calls the predicate name of the pyarrow.types module pyTypes on
+// pyDtype. Older versions of pyarrow do not have all the predicates, a
+// missing predicate is false.
+func pyTypesIs(pyTypes *python3.PyObject, name string, pyDtype *python3.PyObject) (bool, error) {
+	pyPredicate := getPyTypePredicate(pyTypes, name)
+	defer pyPredicate.DecRef()
+	return callPyTypePredicate(pyPredicate, name, pyDtype)
+}
+
+// getPyTypePredicate returns a new reference to the predicate name of the
+// pyarrow.types module pyTypes, or to its fallback. It returns nil, with no
+// exception set, if pyarrow has neither.
+func getPyTypePredicate(pyTypes *python3.PyObject, name string) *python3.PyObject {
+	if pyPredicate := getAttrOrNil(pyTypes, name); pyPredicate != nil {
+		return pyPredicate
+	}
+	if fallback, ok := pyTypePredicateFallbacks[name]; ok {
+		return getAttrOrNil(pyTypes, fallback)
+	}
+	return nil
+}
+
+// callPyTypePredicate calls the predicate name on pyDtype. A nil predicate
+// is false.
+func callPyTypePredicate(pyPredicate *python3.PyObject, name string, pyDtype *python3.PyObject) (bool, error) {
+	if pyPredicate == nil {
+		return false, nil
+	}
+	v := pyPredicate.CallFunctionObjArgs(pyDtype)
+	if v == nil {
+		return false, pyErrorf("could not call pyarrow.types.%s", name)
+	}
+	defer v.DecRef()
+	return v.IsTrue() != 0, nil
+}
+
+// PyDataTypeGetID returns the id of the Python type. The ids of pyarrow and
+// Go arrow types do not always match, use PyDataTypeGetType to identify the
+// type.
 func PyDataTypeGetID(pyDtype *python3.PyObject) (int, error) {
+	checkGIL()
+
 	v, ok := GetIntAttr(pyDtype, "id")
 	if !ok {
-		return 0, errors.New("could not get pyDtype.id")
+		return 0, pyError("could not get pyDtype.id")
 	}
 	return v, nil
 }
@@ -36,7 +456,7 @@ var (
 func GetFromType(t arrow.Type) (arrow.DataType, error) {
 	dtype := dataTypeForType[byte(t&0x1f)]
 	if dtype == nil {
-		return nil, fmt.Errorf("DataType for id=%v is not yet implemented", t)
+		return nil, &UnsupportedTypeError{Type: fmt.Sprint(t), Python: true}
 	}
 	return dtype, nil
 }
@@ -80,3 +500,142 @@ func init() {
 		31: nil,
 	}
 }
+
+// DataTypeToPyDataType returns the pyarrow type given the Go arrow DataType.
+func DataTypeToPyDataType(dtype arrow.DataType) (*python3.PyObject, error) {
+	checkGIL()
+
+	return dataTypeToPyDataType(dtype)
+}
+
+func dataTypeToPyDataType(dtype arrow.DataType) (*python3.PyObject, error) {
+	switch dt := dtype.(type) {
+	case *arrow.TimestampType:
+		pyUnit := python3.PyUnicode_FromString(dt.Unit.String())
+		defer pyUnit.DecRef()
+		if dt.TimeZone == "" {
+			return callPyArrowFunc("timestamp", pyUnit)
+		}
+		pyTz := python3.PyUnicode_FromString(dt.TimeZone)
+		defer pyTz.DecRef()
+		return callPyArrowFunc("timestamp", pyUnit, pyTz)
+
+	case *arrow.Time32Type:
+		return callPyArrowUnitFunc("time32", dt.Unit)
+	case *arrow.Time64Type:
+		return callPyArrowUnitFunc("time64", dt.Unit)
+	case *arrow.DurationType:
+		return callPyArrowUnitFunc("duration", dt.Unit)
+
+	case *arrow.Decimal128Type:
+		pyPrecision := python3.PyLong_FromLong(int(dt.Precision))
+		defer pyPrecision.DecRef()
+		pyScale := python3.PyLong_FromLong(int(dt.Scale))
+		defer pyScale.DecRef()
+		return callPyArrowFunc("decimal128", pyPrecision, pyScale)
+
+	case *DictionaryType:
+		pyIndexType, err := dataTypeToPyDataType(dt.IndexType)
+		if err != nil {
+			return nil, err
+		}
+		defer pyIndexType.DecRef()
+		pyValueType, err := dataTypeToPyDataType(dt.ValueType)
+		if err != nil {
+			return nil, err
+		}
+		defer pyValueType.DecRef()
+		pyOrdered := python3.PyBool_FromLong(0)
+		if dt.Ordered {
+			pyOrdered = python3.PyBool_FromLong(1)
+		}
+		defer pyOrdered.DecRef()
+		return callPyArrowFunc("dictionary", pyIndexType, pyValueType, pyOrdered)
+
+	case *arrow.FixedSizeBinaryType:
+		pyByteWidth := python3.PyLong_FromLong(dt.ByteWidth)
+		defer pyByteWidth.DecRef()
+		return callPyArrowFunc("binary", pyByteWidth)
+
+	case *arrow.ListType:
+		// Maps were converted to lists of key/value structs and go back
+		// as such.
+		pyElem, err := dataTypeToPyDataType(dt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		defer pyElem.DecRef()
+		return callPyArrowFunc("list_", pyElem)
+
+	case *arrow.FixedSizeListType:
+		pyElem, err := dataTypeToPyDataType(dt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		defer pyElem.DecRef()
+		pyListSize := python3.PyLong_FromLong(int(dt.Len()))
+		defer pyListSize.DecRef()
+		return callPyArrowFunc("list_", pyElem, pyListSize)
+
+	case *arrow.StructType:
+		fields := dt.Fields()
+		pyFields := make([]*python3.PyObject, 0, len(fields))
+		defer func() {
+			for i := range pyFields {
+				pyFields[i].DecRef()
+			}
+		}()
+		for i := range fields {
+			pyField, err := fieldToPyField(fields[i])
+			if err != nil {
+				return nil, withPath(err, "field %d %q", i, fields[i].Name)
+			}
+			pyFields = append(pyFields, pyField)
+		}
+		pyFieldList := NewPyList(pyFields)
+		defer pyFieldList.DecRef()
+		return callPyArrowFunc("struct", pyFieldList)
+	}
+
+	factory := pyDataTypeFactoryForType[byte(dtype.ID()&0x1f)]
+	if factory == "" {
+		return nil, &UnsupportedTypeError{Type: fmt.Sprint(dtype), Python: false}
+	}
+	return callPyArrowFunc(factory)
+}
+
+// callPyArrowUnitFunc calls the pyarrow type factory name with the time unit.
+func callPyArrowUnitFunc(name string, unit arrow.TimeUnit) (*python3.PyObject, error) {
+	pyUnit := python3.PyUnicode_FromString(unit.String())
+	defer pyUnit.DecRef()
+	return callPyArrowFunc(name, pyUnit)
+}
+
+var (
+	pyDataTypeFactoryForType [32]string
+)
+
+func init() {
+	pyDataTypeFactoryForType = [...]string{
+		arrow.NULL:    "null",
+		arrow.BOOL:    "bool_",
+		arrow.UINT8:   "uint8",
+		arrow.INT8:    "int8",
+		arrow.UINT16:  "uint16",
+		arrow.INT16:   "int16",
+		arrow.UINT32:  "uint32",
+		arrow.INT32:   "int32",
+		arrow.UINT64:  "uint64",
+		arrow.INT64:   "int64",
+		arrow.FLOAT16: "float16",
+		arrow.FLOAT32: "float32",
+		arrow.FLOAT64: "float64",
+		arrow.STRING:  "string",
+		arrow.BINARY:  "binary",
+		arrow.DATE32:  "date32",
+		arrow.DATE64:  "date64",
+
+		// invalid data types to fill out array size 2⁵-1
+		31: "",
+	}
+}
@@ -0,0 +1,477 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+// pyTableFromFoo converts the table returned by the foo module function
+// pyMethod. The returned table must be Release()'d after use.
+func pyTableFromFoo(t *testing.T, pyMethod string) array.Table {
+	return pyTableFromFooWith(t, pyMethod, nil)
+}
+
+// pyTableFromFooWith is pyTableFromFoo with the Python table passed through
+// prepare, if not nil, before it is converted.
+func pyTableFromFooWith(t *testing.T, pyMethod string, prepare func(*python3.PyObject) (*python3.PyObject, error)) array.Table {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	var table array.Table
+	var err error
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyTable := CallPyFunc(fooModule, pyMethod)
+		if pyTable == nil {
+			python3.PyErr_Print()
+			err = fmt.Errorf("could not call foo.%s", pyMethod)
+			return
+		}
+		defer pyTable.DecRef()
+
+		if prepare != nil {
+			pyPrepared, e := prepare(pyTable)
+			if e != nil {
+				err = e
+				return
+			}
+			defer pyPrepared.DecRef()
+			pyTable = pyPrepared
+		}
+
+		table, err = PyTableToTable(pyTable)
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+// columnStrings returns the string representation of every chunk of the column.
+func columnStrings(col *array.Column) []string {
+	chunks := col.Data().Chunks()
+	strs := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		strs = append(strs, fmt.Sprintf("%v", chunk))
+	}
+	return strs
+}
+
+func testColumn(t *testing.T, table array.Table, i int, wantType string, wantChunks []string) {
+	t.Helper()
+
+	col := table.Column(i)
+	if got := fmt.Sprintf("%v", col.DataType()); got != wantType {
+		t.Fatalf("column %d: got type=%s, want=%s", i, got, wantType)
+	}
+
+	got := columnStrings(col)
+	if len(got) != len(wantChunks) {
+		t.Fatalf("column %d: got=%d chunks, want=%d", i, len(got), len(wantChunks))
+	}
+	for j := range got {
+		if got[j] != wantChunks[j] {
+			t.Fatalf("column %d chunk %d:\ngot= %s\nwant=%s", i, j, got[j], wantChunks[j])
+		}
+	}
+}
+
+func TestNestedTypes(t *testing.T) {
+	table := pyTableFromFoo(t, "nested_types")
+	defer table.Release()
+
+	testColumn(t, table, 0, "list<item: int64>", []string{
+		"[[1 2] (null) [3] []]",
+		"[(null) [3] []]",
+	})
+	testColumn(t, table, 1, "struct<a: int64, b: utf8>", []string{
+		`{[1 (null) 3 (null)] ["x" (null) (null) "z"]}`,
+		`{[(null) 3 (null)] [(null) (null) "z"]}`,
+	})
+
+	if i := table.Schema().FieldIndex("fixed_size_lists"); i < 0 {
+		t.Log("pyarrow has no fixed size list type")
+	} else {
+		testColumn(t, table, i, "fixed_size_list<item: int64>[2]", []string{
+			"[[1 2] (null) [3 4] [5 6]]",
+			"[(null) [3 4] [5 6]]",
+		})
+	}
+
+	if i := table.Schema().FieldIndex("maps"); i < 0 {
+		t.Log("pyarrow has no map type")
+	} else {
+		col := table.Column(i)
+		if got, want := fmt.Sprintf("%v", col.DataType()), "list<item: struct<key: utf8, value: int64>>"; got != want {
+			t.Fatalf("got type=%s, want=%s", got, want)
+		}
+		want := []string{
+			"[[a:1] (null) [b:2 c:(null)] []]",
+			"[(null) [b:2 c:(null)] []]",
+		}
+		chunks := col.Data().Chunks()
+		if len(chunks) != len(want) {
+			t.Fatalf("got=%d chunks, want=%d", len(chunks), len(want))
+		}
+		for j, chunk := range chunks {
+			if got := mapEntries(chunk.(*array.List)); got != want[j] {
+				t.Fatalf("chunk %d: got=%s, want=%s", j, got, want[j])
+			}
+		}
+	}
+}
+
+func TestNestedTypesRoundTrip(t *testing.T) {
+	table := pyTableFromFoo(t, "nested_types")
+	defer table.Release()
+
+	var got array.Table
+	var err error
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyTable, e := TableToPyTable(table)
+		if e != nil {
+			err = e
+			return
+		}
+		defer pyTable.DecRef()
+		got, err = PyTableToTable(pyTable)
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if cerr, ok := err.(*ConversionError); ok {
+		if perr, ok := cerr.Err.(*PythonError); ok && perr.Type == "TypeError" {
+			t.Skipf("pyarrow can not build nested arrays from buffers: %v", err)
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer got.Release()
+
+	// Maps come back as lists of key/value structs.
+	if !got.Schema().Equal(table.Schema()) {
+		t.Fatalf("got schema=%v, want=%v", got.Schema(), table.Schema())
+	}
+	for i := 0; i < int(table.NumCols()); i++ {
+		want := table.Column(i).Data().Chunks()
+		chunks := got.Column(i).Data().Chunks()
+		if len(chunks) != len(want) {
+			t.Fatalf("column %d: got=%d chunks, want=%d", i, len(chunks), len(want))
+		}
+		for j := range chunks {
+			gotChunk, wantChunk := fmt.Sprintf("%v", chunks[j]), fmt.Sprintf("%v", want[j])
+			if l, ok := want[j].(*array.List); ok && table.Schema().Field(i).Name == "maps" {
+				gotChunk, wantChunk = mapEntries(chunks[j].(*array.List)), mapEntries(l)
+			}
+			if gotChunk != wantChunk {
+				t.Fatalf("column %d chunk %d:\ngot= %s\nwant=%s", i, j, gotChunk, wantChunk)
+			}
+		}
+	}
+}
+
+// mapEntries formats the entries of a converted map array. The Go strings
+// of lists of structs do not respect the offsets of the structs.
+func mapEntries(l *array.List) string {
+	entries := l.ListValues().(*array.Struct)
+	keys := entries.Field(0).(*array.String)
+	values := entries.Field(1).(*array.Int64)
+	offsets := l.Offsets()[l.Data().Offset():]
+
+	rows := make([]string, 0, l.Len())
+	for i := 0; i < l.Len(); i++ {
+		if l.IsNull(i) {
+			rows = append(rows, "(null)")
+			continue
+		}
+		row := make([]string, 0, offsets[i+1]-offsets[i])
+		for j := int(offsets[i]); j < int(offsets[i+1]); j++ {
+			value := "(null)"
+			if values.IsValid(j) {
+				value = fmt.Sprint(values.Value(j))
+			}
+			row = append(row, keys.Value(j)+":"+value)
+		}
+		rows = append(rows, "["+strings.Join(row, " ")+"]")
+	}
+	return "[" + strings.Join(rows, " ") + "]"
+}
+
+func TestTemporalTypes(t *testing.T) {
+	table := pyTableFromFoo(t, "temporal_types")
+	defer table.Release()
+
+	wantTypes := []string{
+		"timestamp[ns, tz=UTC]",
+		"timestamp[ms]",
+		"time32[s]",
+		"time64[us]",
+		"duration[ns]",
+	}
+	if table.NumCols() == 4 {
+		// pyarrow < 0.14 has no duration type.
+		wantTypes = wantTypes[:4]
+	}
+	if got, want := int(table.NumCols()), len(wantTypes); got != want {
+		t.Fatalf("got=%d columns, want=%d", got, want)
+	}
+	for i, want := range wantTypes {
+		testColumn(t, table, i, want, []string{"[0 1 (null)]"})
+	}
+}
+
+func TestTemporalTypesRoundTrip(t *testing.T) {
+	table := pyTableFromFoo(t, "temporal_types")
+	defer table.Release()
+
+	got := pyTableRoundTrip(t, table)
+	defer got.Release()
+
+	if !got.Schema().Equal(table.Schema()) {
+		t.Fatalf("got schema=%v, want=%v", got.Schema(), table.Schema())
+	}
+}
+
+// pyTableRoundTrip converts the table to pyarrow and back. The returned
+// table must be Release()'d after use.
+func pyTableRoundTrip(t *testing.T, table array.Table) array.Table {
+	var got array.Table
+	var err error
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyTable, e := TableToPyTable(table)
+		if e != nil {
+			err = e
+			return
+		}
+		defer pyTable.DecRef()
+		got, err = PyTableToTable(pyTable)
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestTemporalTypeAttributes(t *testing.T) {
+	for _, tc := range []struct {
+		unit, tz string // Python expressions
+		wantErr  string
+		wantType string
+		wantTz   string
+	}{
+		{unit: `"ms"`, tz: `None`},
+		{unit: `"ns"`, tz: `"UTC"`, wantTz: "UTC"},
+		{unit: `1`, tz: `2`, wantErr: "pyDtype.{attr} is not a str: {value}"},
+		{unit: `"\ud800"`, tz: `"\ud800"`, wantErr: "could not convert pyDtype.{attr}", wantType: "UnicodeEncodeError"},
+	} {
+		err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+			// A stand-in for a pyarrow type with the given unit and tz.
+			python3.PyRun_SimpleString(fmt.Sprintf(
+				"class TemporalType(object):\n    unit, tz = %s, %s\ntemporal_type = TemporalType()", tc.unit, tc.tz))
+			pyMain := python3.PyImport_AddModule("__main__") // borrowed
+			pyDtype := pyMain.GetAttrString("temporal_type")
+			if pyDtype == nil {
+				t.Error(pyError("could not get temporal_type"))
+				return
+			}
+			defer pyDtype.DecRef()
+
+			_, unitErr := PyDataTypeGetUnit(pyDtype)
+			tz, tzErr := PyDataTypeGetTimeZone(pyDtype)
+			if python3.PyErr_Occurred() != nil {
+				python3.PyErr_Clear()
+				t.Errorf("unit=%s tz=%s: a Python error is still set", tc.unit, tc.tz)
+			}
+
+			if tc.wantErr == "" {
+				if unitErr != nil || tzErr != nil {
+					t.Errorf("unit=%s tz=%s: got errors %v, %v", tc.unit, tc.tz, unitErr, tzErr)
+				}
+				if tz != tc.wantTz {
+					t.Errorf("got tz=%q, want=%q", tz, tc.wantTz)
+				}
+				return
+			}
+			for _, c := range []struct {
+				attr, value string
+				err         error
+			}{{"unit", tc.unit, unitErr}, {"tz", tc.tz, tzErr}} {
+				want := strings.NewReplacer("{attr}", c.attr, "{value}", c.value).Replace(tc.wantErr)
+				if tc.wantType != "" {
+					perr, ok := c.err.(*PythonError)
+					if !ok || perr.Op != want || perr.Type != tc.wantType {
+						t.Errorf("got error=%v, want op=%q type=%s", c.err, want, tc.wantType)
+					}
+				} else if c.err == nil || c.err.Error() != want {
+					t.Errorf("got error=%v, want=%s", c.err, want)
+				}
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDecimalAndFixedSizeBinary(t *testing.T) {
+	table := pyTableFromFoo(t, "decimal_and_fixed_size_binary")
+	defer table.Release()
+
+	testColumn(t, table, 0, "decimal(5, 2)", []string{
+		"[{123 0} (null) {18446744073709551160 -1}]",
+	})
+	testColumn(t, table, 1, "fixed_size_binary[16]", []string{
+		`["0123456789abcdef" (null) "fedcba9876543210"]`,
+	})
+}
+
+func TestPrimitiveTypes(t *testing.T) {
+	table := pyTableFromFoo(t, "primitive_types")
+	defer table.Release()
+
+	wantTypes := []string{
+		"null", "bool",
+		"uint8", "int8", "uint16", "int16",
+		"uint32", "int32", "uint64", "int64",
+		"float32", "float64",
+		"utf8", "binary", "date32", "date64",
+	}
+	fields := table.Schema().Fields()
+	if len(fields) != len(wantTypes) {
+		t.Fatalf("got=%d fields, want=%d", len(fields), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if got := fmt.Sprintf("%v", fields[i].Type); got != want {
+			t.Fatalf("field %q: got type=%s, want=%s", fields[i].Name, got, want)
+		}
+	}
+}
+
+func TestUnsupportedType(t *testing.T) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	var err error
+	skip := false
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyTable := CallPyFunc(fooModule, "unsupported_type")
+		if pyTable == nil {
+			python3.PyErr_Print()
+			err = fmt.Errorf("could not call foo.unsupported_type")
+			return
+		}
+		defer pyTable.DecRef()
+		if pyTable == python3.Py_None {
+			skip = true
+			return
+		}
+
+		var table array.Table
+		table, err = PyTableToTable(pyTable)
+		if err == nil {
+			table.Release()
+		}
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if skip {
+		t.Skip("pyarrow has no large_string type")
+	}
+	if err == nil {
+		t.Fatal("expected an error for the large_string type")
+	}
+	if want := `field 1 "large": pyarrow type large_string is not supported`; err.Error() != want {
+		t.Fatalf("got error=%q, want=%q", err, want)
+	}
+
+	cerr, ok := err.(*ConversionError)
+	if !ok {
+		t.Fatalf("got error of type %T, want=*ConversionError", err)
+	}
+	terr, ok := cerr.Err.(*UnsupportedTypeError)
+	if !ok {
+		t.Fatalf("got cause of type %T, want=*UnsupportedTypeError", cerr.Err)
+	}
+	if terr.Type != "large_string" || !terr.Python {
+		t.Fatalf("got %+v, want the pyarrow type large_string", terr)
+	}
+}
+
+func TestDecimal256(t *testing.T) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	var err error
+	skip := false
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyTable := CallPyFunc(fooModule, "decimal256_type")
+		if pyTable == nil {
+			python3.PyErr_Print()
+			err = fmt.Errorf("could not call foo.decimal256_type")
+			return
+		}
+		defer pyTable.DecRef()
+		if pyTable == python3.Py_None {
+			skip = true
+			return
+		}
+
+		var table array.Table
+		table, err = PyTableToTable(pyTable)
+		if err == nil {
+			table.Release()
+		}
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if skip {
+		t.Skip("pyarrow has no decimal256 type")
+	}
+	// Not decimal128 nor fixed_size_binary[32], whose values would be read
+	// with the wrong width.
+	if want := `field 0 "d": pyarrow type decimal256(40, 2) is not supported`; err == nil || err.Error() != want {
+		t.Fatalf("got error=%v, want=%q", err, want)
+	}
+}
+
+func TestNonNullColumns(t *testing.T) {
+	table := pyTableFromFoo(t, "non_null_columns")
+	defer table.Release()
+
+	wantNullable := []bool{false, false, true}
+	fields := table.Schema().Fields()
+	for i, want := range wantNullable {
+		if got := fields[i].Nullable; got != want {
+			t.Fatalf("field %q: got nullable=%t, want=%t", fields[i].Name, got, want)
+		}
+	}
+
+	testColumn(t, table, 0, "int64", []string{"[1 2 3]"})
+	testColumn(t, table, 1, "utf8", []string{`["a" "b" "c"]`})
+	testColumn(t, table, 2, "float64", []string{"[1.5 2.5 3.5]"})
+
+	// None of the columns have nulls, so pyarrow does not allocate the
+	// validity bitmaps.
+	for i := 0; i < int(table.NumCols()); i++ {
+		chunk := table.Column(i).Data().Chunk(0)
+		if b := chunk.Data().Buffers()[0]; b != nil {
+			t.Fatalf("column %d: got validity buffer of len=%d, want=nil", i, b.Len())
+		}
+		if n := chunk.NullN(); n != 0 {
+			t.Fatalf("column %d: got null count=%d, want=0", i, n)
+		}
+	}
+}
@@ -0,0 +1,520 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// DictionaryType is the type of dictionary encoded arrays. Go arrow does
+// not have a dictionary type yet so the bridge provides its own.
+//
+// The dictionary itself is not part of the type, every chunk carries its
+// own dictionary.
+//
+// Go arrow itself does not know how to build an array of this type from its
+// Data: array.MakeFromData and array.NewSlice panic with "unsupported data
+// type", and so do the APIs built on them, e.g. Column.NewSlice,
+// Chunked.NewSlice and array.NewTableReader with a chunk size. The
+// chunks of a Column or Chunked of this type can be used as *Dictionary
+// arrays, and whole tables and records can be read and passed back to
+// Python; to slice them, convert with DictionaryDecode instead.
+//
+// Only columns can be dictionary encoded: nested types with a dictionary
+// child, e.g. list<dictionary>, are an UnsupportedTypeError.
+type DictionaryType struct {
+	IndexType arrow.DataType
+	ValueType arrow.DataType
+	Ordered   bool
+}
+
+func (*DictionaryType) ID() arrow.Type { return arrow.DICTIONARY }
+func (*DictionaryType) Name() string   { return "dictionary" }
+func (t *DictionaryType) String() string {
+	return fmt.Sprintf("%s<values=%v, indices=%v, ordered=%t>", t.Name(), t.ValueType, t.IndexType, t.Ordered)
+}
+
+// BitWidth returns the bit width of the indices.
+func (t *DictionaryType) BitWidth() int {
+	if idx, ok := t.IndexType.(arrow.FixedWidthDataType); ok {
+		return idx.BitWidth()
+	}
+	return 0
+}
+
+// Dictionary is a dictionary encoded array.
+//
+// The array Data of a Dictionary holds the buffers of the indices and has
+// the Data of the dictionary as its only child.
+type Dictionary struct {
+	refCount   int64
+	data       *array.Data
+	indices    array.Interface
+	dictionary array.Interface
+}
+
+// NewDictionaryData returns a new Dictionary array for the data.
+func NewDictionaryData(data *array.Data) *Dictionary {
+	dt := data.DataType().(*DictionaryType)
+
+	indicesData := array.NewData(dt.IndexType, data.Len(), data.Buffers(), nil, data.NullN(), data.Offset())
+	defer indicesData.Release()
+
+	data.Retain()
+	return &Dictionary{
+		refCount:   1,
+		data:       data,
+		indices:    array.MakeFromData(indicesData),
+		dictionary: dictionaryFromData(data),
+	}
+}
+
+// dictionaryFromData returns the dictionary held as the only child of data.
+// Go arrow does not export the children of array Data, but a struct array
+// built over the data exposes them as its fields. This relies on
+// array.NewStructData not checking the type of data, which holds for the Go
+// arrow version this module requires.
+func dictionaryFromData(data *array.Data) array.Interface {
+	s := array.NewStructData(data)
+	defer s.Release()
+
+	dictionary := s.Field(0)
+	dictionary.Retain()
+	return dictionary
+}
+
+// Indices returns the indices into the dictionary.
+func (a *Dictionary) Indices() array.Interface { return a.indices }
+
+// Dictionary returns the values of the dictionary.
+func (a *Dictionary) Dictionary() array.Interface { return a.dictionary }
+
+func (a *Dictionary) DataType() arrow.DataType { return a.data.DataType() }
+func (a *Dictionary) NullN() int               { return a.indices.NullN() }
+func (a *Dictionary) NullBitmapBytes() []byte  { return a.indices.NullBitmapBytes() }
+func (a *Dictionary) IsNull(i int) bool        { return a.indices.IsNull(i) }
+func (a *Dictionary) IsValid(i int) bool       { return a.indices.IsValid(i) }
+func (a *Dictionary) Data() *array.Data        { return a.data }
+func (a *Dictionary) Len() int                 { return a.data.Len() }
+
+// Retain increases the reference count by 1.
+func (a *Dictionary) Retain() {
+	atomic.AddInt64(&a.refCount, 1)
+}
+
+// Release decreases the reference count by 1.
+// When the reference count goes to zero, the memory is freed.
+func (a *Dictionary) Release() {
+	if atomic.AddInt64(&a.refCount, -1) == 0 {
+		a.indices.Release()
+		a.dictionary.Release()
+		a.data.Release()
+		a.indices, a.dictionary, a.data = nil, nil, nil
+	}
+}
+
+func (a *Dictionary) String() string {
+	o := new(strings.Builder)
+	fmt.Fprintf(o, "{%v %v}", a.dictionary, a.indices)
+	return o.String()
+}
+
+var _ array.Interface = (*Dictionary)(nil)
+
+// makeFromData is array.MakeFromData with support for the dictionary
+// type of the bridge. Only top level dictionaries are supported, see
+// hasDictionaryChild.
+func makeFromData(data *array.Data) array.Interface {
+	if _, ok := data.DataType().(*DictionaryType); ok {
+		return NewDictionaryData(data)
+	}
+	return array.MakeFromData(data)
+}
+
+// hasDictionaryChild reports whether a child of dtype, at any depth, is
+// dictionary encoded. Go arrow builds the children of nested arrays with
+// array.MakeFromData, which panics on them, so such types are rejected.
+func hasDictionaryChild(dtype arrow.DataType) bool {
+	var children []arrow.DataType
+	switch dt := dtype.(type) {
+	case *arrow.ListType:
+		children = []arrow.DataType{dt.Elem()}
+	case *arrow.FixedSizeListType:
+		children = []arrow.DataType{dt.Elem()}
+	case *arrow.StructType:
+		for _, field := range dt.Fields() {
+			children = append(children, field.Type)
+		}
+	}
+	for _, child := range children {
+		if _, ok := child.(*DictionaryType); ok || hasDictionaryChild(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// pyDictionaryDataTypeToDataType builds the Go DictionaryType of a Python
+// dictionary type.
+func pyDictionaryDataTypeToDataType(pyDtype *python3.PyObject) (arrow.DataType, error) {
+	indexType, err := pyDataTypeAttrToDataType(pyDtype, "index_type")
+	if err != nil {
+		return nil, err
+	}
+	valueType, err := pyDataTypeAttrToDataType(pyDtype, "value_type")
+	if err != nil {
+		return nil, err
+	}
+
+	pyOrdered := pyAttr(pyDtype, "ordered")
+	if pyOrdered == nil {
+		return nil, pyError("could not get pyDtype.ordered")
+	}
+	defer pyOrdered.DecRef()
+
+	return &DictionaryType{
+		IndexType: indexType,
+		ValueType: valueType,
+		Ordered:   pyOrdered.IsTrue() != 0,
+	}, nil
+}
+
+// pyChunkDictionaryToData returns the Go data of the dictionary of a
+// dictionary encoded pyChunk.
+func (c *Converter) pyChunkDictionaryToData(pyChunk *python3.PyObject, dt *DictionaryType) (*array.Data, error) {
+	pyDictionary := pyAttr(pyChunk, "dictionary")
+	if pyDictionary == nil {
+		return nil, pyError("could not get pyChunk.dictionary")
+	}
+	defer pyDictionary.DecRef()
+
+	return c.pyChunkToData(pyDictionary, dt.ValueType)
+}
+
+// DictionaryMode controls how dictionary encoded columns are converted.
+type DictionaryMode int
+
+const (
+	// DictionaryKeep converts every chunk with its own dictionary.
+	DictionaryKeep DictionaryMode = iota
+	// DictionaryUnify makes all the chunks of a column share one dictionary.
+	DictionaryUnify
+	// DictionaryDecode converts the chunks to the plain value type.
+	DictionaryDecode
+)
+
+func (m DictionaryMode) String() string {
+	switch m {
+	case DictionaryKeep:
+		return "keep"
+	case DictionaryUnify:
+		return "unify"
+	case DictionaryDecode:
+		return "decode"
+	}
+	return fmt.Sprintf("DictionaryMode(%d)", int(m))
+}
+
+// PyTableWithDictionaryMode returns a pyarrow Table where the dictionary
+// encoded columns of pyTable are unified or decoded according to mode.
+// The table can then be converted with PyTableToTable.
+func PyTableWithDictionaryMode(pyTable *python3.PyObject, mode DictionaryMode) (*python3.PyObject, error) {
+	checkGIL()
+
+	return pyTableWithDictionaryMode(pyTable, mode)
+}
+
+func pyTableWithDictionaryMode(pyTable *python3.PyObject, mode DictionaryMode) (*python3.PyObject, error) {
+	pySchema, err := pySchemaFromPyTable(pyTable)
+	if err != nil {
+		return nil, err
+	}
+	defer pySchema.DecRef()
+
+	numCols, ok := GetIntAttr(pyTable, "num_columns")
+	if !ok {
+		return nil, pyError("could not get pyTable.num_columns")
+	}
+
+	pyArrays := make([]*python3.PyObject, 0, numCols)
+	pyFields := make([]*python3.PyObject, 0, numCols)
+	defer func() {
+		for i := range pyArrays {
+			pyArrays[i].DecRef()
+		}
+		for i := range pyFields {
+			pyFields[i].DecRef()
+		}
+	}()
+
+	changed := false
+	for i := 0; i < numCols; i++ {
+		pyIndex := python3.PyLong_FromLong(i)
+		pyColumn := CallPyFunc(pyTable, "column", pyIndex)
+		pyField := pySchema.GetItem(pyIndex)
+		pyIndex.DecRef()
+		if pyColumn == nil {
+			return nil, pyError("could not get pyColumn")
+		}
+		if pyField == nil {
+			pyColumn.DecRef()
+			return nil, pyError("could not get pyField")
+		}
+		pyFields = append(pyFields, pyField)
+
+		pyChunked, err := pyColumnGetPyChunked(pyColumn)
+		pyColumn.DecRef()
+		if err != nil {
+			return nil, err
+		}
+
+		pyNewChunked, err := pyChunkedWithDictionaryMode(pyChunked, mode)
+		pyChunked.DecRef()
+		if err != nil {
+			return nil, err
+		}
+		pyArrays = append(pyArrays, pyNewChunked)
+
+		if pyNewChunked != pyChunked {
+			changed = true
+			pyField, err := pyFieldWithTypeOf(pyFields[i], pyNewChunked)
+			if err != nil {
+				return nil, err
+			}
+			pyFields[i].DecRef()
+			pyFields[i] = pyField
+		}
+	}
+
+	if !changed {
+		pyTable.IncRef()
+		return pyTable, nil
+	}
+
+	pyFieldList := NewPyList(pyFields)
+	defer pyFieldList.DecRef()
+
+	pyMetadata := pyAttr(pySchema, "metadata")
+	if pyMetadata == nil {
+		return nil, pyError("could not get pySchema.metadata")
+	}
+	defer pyMetadata.DecRef()
+
+	pyNewSchema, err := callPyArrowFunc("schema", pyFieldList, pyMetadata)
+	if err != nil {
+		return nil, err
+	}
+	defer pyNewSchema.DecRef()
+
+	pyArrayList := NewPyList(pyArrays)
+	defer pyArrayList.DecRef()
+
+	pyTableType, err := getPyArrowAttr("Table")
+	if err != nil {
+		return nil, err
+	}
+	defer pyTableType.DecRef()
+
+	pyNewTable := CallPyFuncKwargs(pyTableType, "from_arrays",
+		[]*python3.PyObject{pyArrayList},
+		map[string]*python3.PyObject{"schema": pyNewSchema},
+	)
+	if pyNewTable == nil {
+		return nil, pyError("could not call pyarrow.Table.from_arrays")
+	}
+	return pyNewTable, nil
+}
+
+// pyFieldWithTypeOf returns a copy of pyField with the type of pyChunked.
+func pyFieldWithTypeOf(pyField, pyChunked *python3.PyObject) (*python3.PyObject, error) {
+	pyName := pyAttr(pyField, "name")
+	if pyName == nil {
+		return nil, pyError("could not get pyField.name")
+	}
+	defer pyName.DecRef()
+
+	pyNullable := pyAttr(pyField, "nullable")
+	if pyNullable == nil {
+		return nil, pyError("could not get pyField.nullable")
+	}
+	defer pyNullable.DecRef()
+
+	pyMetadata := pyAttr(pyField, "metadata")
+	if pyMetadata == nil {
+		return nil, pyError("could not get pyField.metadata")
+	}
+	defer pyMetadata.DecRef()
+
+	pyDtype := pyAttr(pyChunked, "type")
+	if pyDtype == nil {
+		return nil, pyError("could not get pyChunked.type")
+	}
+	defer pyDtype.DecRef()
+
+	return callPyArrowFunc("field", pyName, pyDtype, pyNullable, pyMetadata)
+}
+
+// PyChunkedWithDictionaryMode returns a pyarrow ChunkedArray where the
+// chunks of a dictionary encoded pyChunked are unified or decoded according
+// to mode. pyChunked itself is returned, with a new reference, when there
+// is nothing to do.
+func PyChunkedWithDictionaryMode(pyChunked *python3.PyObject, mode DictionaryMode) (*python3.PyObject, error) {
+	checkGIL()
+
+	return pyChunkedWithDictionaryMode(pyChunked, mode)
+}
+
+func pyChunkedWithDictionaryMode(pyChunked *python3.PyObject, mode DictionaryMode) (*python3.PyObject, error) {
+	pyDtype := pyAttr(pyChunked, "type")
+	if pyDtype == nil {
+		return nil, pyError("could not get pyChunked.type")
+	}
+	defer pyDtype.DecRef()
+
+	pyTypes, err := getPyArrowAttr("types")
+	if err != nil {
+		return nil, err
+	}
+	defer pyTypes.DecRef()
+
+	isDictionary, err := pyTypesIs(pyTypes, "is_dictionary", pyDtype)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isDictionary || mode == DictionaryKeep {
+		pyChunked.IncRef()
+		return pyChunked, nil
+	}
+
+	switch mode {
+	case DictionaryUnify:
+		return pyChunkedUnifyDictionaries(pyChunked)
+	case DictionaryDecode:
+		return pyChunkedDecodeDictionaries(pyChunked, pyDtype)
+	}
+	return nil, fmt.Errorf("unknown dictionary mode %v", mode)
+}
+
+func pyChunkedUnifyDictionaries(pyChunked *python3.PyObject) (*python3.PyObject, error) {
+	if pyChunked.HasAttrString("unify_dictionaries") {
+		pyUnified := CallPyFunc(pyChunked, "unify_dictionaries")
+		if pyUnified == nil {
+			return nil, pyError("could not call pyChunked.unify_dictionaries()")
+		}
+		return pyUnified, nil
+	}
+
+	// Older versions of pyarrow can only encode the decoded values again.
+	pyDtype := pyAttr(pyChunked, "type")
+	if pyDtype == nil {
+		return nil, pyError("could not get pyChunked.type")
+	}
+	defer pyDtype.DecRef()
+
+	pyDecoded, err := pyChunkedDecodeDictionaries(pyChunked, pyDtype)
+	if err != nil {
+		return nil, err
+	}
+	defer pyDecoded.DecRef()
+
+	pyUnified := CallPyFunc(pyDecoded, "dictionary_encode")
+	if pyUnified == nil {
+		return nil, pyError("could not call pyChunked.dictionary_encode()")
+	}
+	return pyUnified, nil
+}
+
+func pyChunkedDecodeDictionaries(pyChunked, pyDtype *python3.PyObject) (*python3.PyObject, error) {
+	pyValueType := pyAttr(pyDtype, "value_type")
+	if pyValueType == nil {
+		return nil, pyError("could not get pyDtype.value_type")
+	}
+	defer pyValueType.DecRef()
+
+	pyChunks, err := pyChunkedGetPyChunks(pyChunked)
+	if err != nil {
+		return nil, err
+	}
+	defer pyChunks.DecRef()
+
+	length := python3.PyList_Size(pyChunks)
+	pyDecoded := make([]*python3.PyObject, 0, length)
+	defer func() {
+		for i := range pyDecoded {
+			pyDecoded[i].DecRef()
+		}
+	}()
+
+	for i := 0; i < length; i++ {
+		pyChunk := python3.PyList_GetItem(pyChunks, i)
+		if pyChunk == nil {
+			return nil, pyError("could not get pyChunk from list")
+		}
+		pyValues := CallPyFunc(pyChunk, "dictionary_decode")
+		if pyValues == nil {
+			return nil, pyError("could not call pyChunk.dictionary_decode()")
+		}
+		pyDecoded = append(pyDecoded, pyValues)
+	}
+
+	pyDecodedList := NewPyList(pyDecoded)
+	defer pyDecodedList.DecRef()
+
+	return callPyArrowFunc("chunked_array", pyDecodedList, pyValueType)
+}
+
+// dictionaryChunkToPyChunk returns a pyarrow DictionaryArray sharing the
+// buffers of the Go dictionary chunk.
+func dictionaryChunkToPyChunk(chunk *Dictionary) (*python3.PyObject, error) {
+	dt := chunk.DataType().(*DictionaryType)
+
+	pyIndexType, err := dataTypeToPyDataType(dt.IndexType)
+	if err != nil {
+		return nil, err
+	}
+	defer pyIndexType.DecRef()
+
+	pyIndices, err := chunkToPyChunk(chunk.Indices(), pyIndexType)
+	if err != nil {
+		return nil, err
+	}
+	defer pyIndices.DecRef()
+
+	pyValueType, err := dataTypeToPyDataType(dt.ValueType)
+	if err != nil {
+		return nil, err
+	}
+	defer pyValueType.DecRef()
+
+	pyDictionary, err := chunkToPyChunk(chunk.Dictionary(), pyValueType)
+	if err != nil {
+		return nil, err
+	}
+	defer pyDictionary.DecRef()
+
+	pyDictionaryArrayType, err := getPyArrowAttr("DictionaryArray")
+	if err != nil {
+		return nil, err
+	}
+	defer pyDictionaryArrayType.DecRef()
+
+	pyOrdered := python3.PyBool_FromLong(0)
+	if dt.Ordered {
+		pyOrdered = python3.PyBool_FromLong(1)
+	}
+	defer pyOrdered.DecRef()
+
+	pyChunk := CallPyFuncKwargs(pyDictionaryArrayType, "from_arrays",
+		[]*python3.PyObject{pyIndices, pyDictionary},
+		map[string]*python3.PyObject{"ordered": pyOrdered},
+	)
+	if pyChunk == nil {
+		return nil, pyError("could not call pyarrow.DictionaryArray.from_arrays")
+	}
+	return pyChunk, nil
+}
@@ -0,0 +1,227 @@
+package bridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// skipWithoutChunkDictionaries skips the test if the installed pyarrow
+// keeps the dictionary in the type, see foo.chunk_dictionaries.
+func skipWithoutChunkDictionaries(t *testing.T) {
+	t.Helper()
+
+	supported := false
+	withFooResult(t, "chunk_dictionaries", func(pySupported *python3.PyObject) error {
+		supported = pySupported.IsTrue() != 0
+		return nil
+	})
+	if !supported {
+		t.Skip("pyarrow has no per chunk dictionaries")
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	skipWithoutChunkDictionaries(t)
+
+	t.Run("keep", func(t *testing.T) {
+		table := pyTableFromFoo(t, "dictionary_chunks")
+		defer table.Release()
+
+		testColumn(t, table, 0, "dictionary<values=utf8, indices=int8, ordered=false>", []string{
+			`{["a" "b"] [0 1 (null) 0]}`,
+			`{["c" "a"] [1 0]}`,
+		})
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		table := pyTableFromFooWith(t, "dictionary_chunks", func(pyTable *python3.PyObject) (*python3.PyObject, error) {
+			return PyTableWithDictionaryMode(pyTable, DictionaryDecode)
+		})
+		defer table.Release()
+
+		testColumn(t, table, 0, "utf8", []string{
+			`["a" "b" (null) "a"]`,
+			`["a" "c"]`,
+		})
+	})
+
+	t.Run("unify", func(t *testing.T) {
+		table := pyTableFromFooWith(t, "dictionary_chunks", func(pyTable *python3.PyObject) (*python3.PyObject, error) {
+			return PyTableWithDictionaryMode(pyTable, DictionaryUnify)
+		})
+		defer table.Release()
+
+		chunks := table.Column(0).Data().Chunks()
+		if len(chunks) != 2 {
+			t.Fatalf("got=%d chunks, want=2", len(chunks))
+		}
+		for i, chunk := range chunks {
+			dict, ok := chunk.(*Dictionary)
+			if !ok {
+				t.Fatalf("chunk %d: got=%T, want=*Dictionary", i, chunk)
+			}
+			if got, want := fmt.Sprintf("%v", dict.Dictionary()), `["a" "b" "c"]`; got != want {
+				t.Fatalf("chunk %d: got dictionary=%s, want=%s", i, got, want)
+			}
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		table := pyTableFromFoo(t, "dictionary_chunks")
+		defer table.Release()
+
+		got := pyTableRoundTrip(t, table)
+		defer got.Release()
+
+		testColumn(t, got, 0, "dictionary<values=utf8, indices=int8, ordered=false>", []string{
+			`{["a" "b"] [0 1 (null) 0]}`,
+			`{["c" "a"] [1 0]}`,
+		})
+	})
+}
+
+// panics reports whether fn panics.
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		if recover() != nil {
+			panicked = true
+		}
+	}()
+	fn()
+	return false
+}
+
+// TestDictionaryGoArrowAPIs documents which Go arrow APIs work on the
+// chunks of a DictionaryType column, see DictionaryType.
+func TestDictionaryGoArrowAPIs(t *testing.T) {
+	skipWithoutChunkDictionaries(t)
+
+	table := pyTableFromFoo(t, "dictionary_chunks")
+	defer table.Release()
+
+	col := table.Column(0)
+	chunk := col.Data().Chunk(0)
+
+	t.Run("safe", func(t *testing.T) {
+		if _, ok := chunk.(*Dictionary); !ok {
+			t.Fatalf("got=%T, want=*Dictionary", chunk)
+		}
+		if got, want := chunk.Len(), 4; got != want {
+			t.Fatalf("got len=%d, want=%d", got, want)
+		}
+
+		r := array.NewTableReader(table, -1)
+		defer r.Release()
+		n := 0
+		for r.Next() {
+			n += int(r.Record().NumRows())
+		}
+		if n != 6 {
+			t.Fatalf("got=%d rows, want=6", n)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		fn   func()
+	}{
+		{"array.NewSlice", func() { array.NewSlice(chunk, 0, 1).Release() }},
+		{"array.MakeFromData", func() { array.MakeFromData(chunk.Data()).Release() }},
+		{"Column.NewSlice", func() { col.NewSlice(0, 1).Release() }},
+		{"Chunked.NewSlice", func() { col.Data().NewSlice(0, 1).Release() }},
+		{"array.NewTableReader chunk size", func() {
+			r := array.NewTableReader(table, 1)
+			defer r.Release()
+			for r.Next() {
+			}
+		}},
+	} {
+		t.Run("unsafe "+tc.name, func(t *testing.T) {
+			if !panics(tc.fn) {
+				t.Fatalf("%s did not panic, update the documentation of DictionaryType", tc.name)
+			}
+		})
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		table := pyTableFromFooWith(t, "dictionary_chunks", func(pyTable *python3.PyObject) (*python3.PyObject, error) {
+			return PyTableWithDictionaryMode(pyTable, DictionaryDecode)
+		})
+		defer table.Release()
+
+		slice := table.Column(0).NewSlice(1, 5)
+		defer slice.Release()
+		if got, want := columnStrings(slice), []string{`["b" (null) "a"]`, `["a"]`}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("got=%q, want=%q", got, want)
+		}
+	})
+}
+
+func TestNestedDictionary(t *testing.T) {
+	convert := func(t *testing.T, conv *Converter) (array.Table, error) {
+		t.Helper()
+		skipWithoutChunkDictionaries(t)
+
+		var table array.Table
+		var convErr error
+		withFooResult(t, "nested_dictionary", func(pyTable *python3.PyObject) error {
+			table, convErr = conv.PyTableToTable(pyTable)
+			return nil
+		})
+		return table, convErr
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		table, err := convert(t, defaultConverter)
+		if err == nil {
+			table.Release()
+			t.Fatal("got no error for a list of dictionaries")
+		}
+		cerr, ok := err.(*ConversionError)
+		if !ok || !isUnsupportedType(err) {
+			t.Fatalf("got error=%v of type %T, want an unsupported type", err, err)
+		}
+		if got, want := cerr.Path[0], `field 1 "lists"`; got != want {
+			t.Fatalf("got path=%q, want=%q", got, want)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		conv, err := NewConverter(WithUnknownTypes(LenientTypes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		table, err := convert(t, conv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer table.Release()
+
+		if got, want := fmt.Sprintf("%v", tableNames(table)), "[ok]"; got != want {
+			t.Fatalf("got columns=%s, want=%s", got, want)
+		}
+	})
+
+	t.Run("go types", func(t *testing.T) {
+		dict := &DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+		for _, tc := range []struct {
+			dtype arrow.DataType
+			want  bool
+		}{
+			{dict, false},
+			{arrow.ListOf(arrow.BinaryTypes.String), false},
+			{arrow.ListOf(dict), true},
+			{arrow.FixedSizeListOf(2, dict), true},
+			{arrow.StructOf(arrow.Field{Name: "d", Type: dict}), true},
+			{arrow.ListOf(arrow.StructOf(arrow.Field{Name: "value", Type: dict})), true},
+		} {
+			if got := hasDictionaryChild(tc.dtype); got != tc.want {
+				t.Errorf("%v: got=%t, want=%t", tc.dtype, got, tc.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/go-python3"
+)
+
+// PythonError is returned when a call into Python fails. It holds the
+// Python exception that was pending, if any, which is cleared from the
+// interpreter.
+type PythonError struct {
+	// Op describes what the bridge was doing, e.g. "could not get pyChunk".
+	Op string
+	// Type is the name of the exception type, e.g. "ValueError", or "" if
+	// no exception was pending.
+	Type string
+	// Message is str() of the exception.
+	Message string
+	// Traceback is the formatted traceback of the exception.
+	Traceback string
+}
+
+func (e *PythonError) Error() string {
+	if e.Type == "" {
+		return e.Op
+	}
+	return e.Op + ": " + e.Type + ": " + e.Message
+}
+
+// UnsupportedTypeError is returned when a type has no equivalent on the
+// other side of the bridge.
+type UnsupportedTypeError struct {
+	// Type is the string representation of the type.
+	Type string
+	// Python is true when Type is a pyarrow type and false when it is a Go
+	// arrow type.
+	Python bool
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	if e.Python {
+		return fmt.Sprintf("pyarrow type %s is not supported", e.Type)
+	}
+	return fmt.Sprintf("Go arrow type %s is not supported", e.Type)
+}
+
+// DuplicateFieldError is returned when two fields of a schema or struct
+// have the same name. Arrow allows it but Go arrow schemas and struct types
+// can not hold such fields; the columns of a table can still be converted
+// if only one of them is selected, see WithColumnIndices.
+type DuplicateFieldError struct {
+	// Name is the duplicated field name.
+	Name string
+	// Indices are the positions of the first two fields called Name.
+	Indices [2]int
+}
+
+func (e *DuplicateFieldError) Error() string {
+	return fmt.Sprintf("fields %d and %d are both named %q, Go arrow requires unique field names", e.Indices[0], e.Indices[1], e.Name)
+}
+
+// ConversionError records where in a table a conversion failed, e.g.
+// `column 2 "price": chunk 3: buffer 1`.
+type ConversionError struct {
+	Path []string
+	Err  error
+}
+
+func (e *ConversionError) Error() string {
+	return strings.Join(e.Path, ": ") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, e.g. a *PythonError.
+func (e *ConversionError) Unwrap() error { return e.Err }
+
+// withPath prepends the path element to err. The ConversionError of a
+// nested failure is extended rather than wrapped again so the path reads
+// from the table down to the failing buffer.
+func withPath(err error, format string, args ...interface{}) error {
+	elem := fmt.Sprintf(format, args...)
+	if cerr, ok := err.(*ConversionError); ok {
+		return &ConversionError{
+			Path: append([]string{elem}, cerr.Path...),
+			Err:  cerr.Err,
+		}
+	}
+	return &ConversionError{Path: []string{elem}, Err: err}
+}
+
+// pyError returns a *PythonError for the failed operation op holding the
+// pending Python exception, which is cleared. It must be called with the
+// GIL held, before any other call into Python.
+func pyError(op string) error {
+	e := &PythonError{Op: op}
+
+	pyType, pyValue, pyTraceback := python3.PyErr_Fetch()
+	if pyType == nil {
+		return e
+	}
+	pyType, pyValue, pyTraceback = python3.PyErr_NormalizeException(pyType, pyValue, pyTraceback)
+	defer func() {
+		pyType.DecRef()
+		pyValue.DecRef()
+		pyTraceback.DecRef()
+	}()
+
+	e.Type = pyExceptionTypeName(pyType)
+	if pyValue != nil {
+		e.Message = PyObjectString(pyValue)
+	}
+	e.Traceback = pyFormatException(pyType, pyValue, pyTraceback)
+	return e
+}
+
+// pyErrorf is pyError with a formatted op.
+func pyErrorf(format string, args ...interface{}) error {
+	return pyError(fmt.Sprintf(format, args...))
+}
+
+func pyExceptionTypeName(pyType *python3.PyObject) string {
+	pyName := pyType.GetAttrString("__name__")
+	if pyName == nil {
+		python3.PyErr_Clear()
+		return PyObjectString(pyType)
+	}
+	defer pyName.DecRef()
+	return python3.PyUnicode_AsUTF8(pyName)
+}
+
+// pyFormatException returns the exception formatted the way Python prints
+// it, or "" if it cannot be formatted.
+func pyFormatException(pyType, pyValue, pyTraceback *python3.PyObject) string {
+	pyTracebackModule := python3.PyImport_ImportModule("traceback")
+	if pyTracebackModule == nil {
+		python3.PyErr_Clear()
+		return ""
+	}
+	defer pyTracebackModule.DecRef()
+
+	args := []*python3.PyObject{pyType, pyValue, pyTraceback}
+	for i := range args {
+		if args[i] == nil {
+			args[i] = python3.Py_None
+		}
+	}
+
+	pyLines := CallPyFunc(pyTracebackModule, "format_exception", args...)
+	if pyLines == nil {
+		python3.PyErr_Clear()
+		return ""
+	}
+	defer pyLines.DecRef()
+
+	var b strings.Builder
+	for i := 0; i < python3.PyList_Size(pyLines); i++ {
+		b.WriteString(python3.PyUnicode_AsUTF8(python3.PyList_GetItem(pyLines, i)))
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestPythonError(t *testing.T) {
+	var err error
+	var pending bool
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		// An int has no schema, converting it raises an AttributeError.
+		pyNotATable := python3.PyLong_FromLong(42)
+		defer pyNotATable.DecRef()
+
+		var table array.Table
+		table, err = PyTableToTable(pyNotATable)
+		if err == nil {
+			table.Release()
+		}
+		pending = python3.PyErr_Occurred() != nil
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+
+	if pending {
+		t.Fatal("the Python exception was left pending")
+	}
+	if err == nil {
+		t.Fatal("expected an error converting an int")
+	}
+
+	perr, ok := err.(*PythonError)
+	if !ok {
+		t.Fatalf("got error of type %T, want=*PythonError", err)
+	}
+	if perr.Op != "could not get pySchema" {
+		t.Fatalf("got op=%q, want=%q", perr.Op, "could not get pySchema")
+	}
+	if perr.Type != "AttributeError" {
+		t.Fatalf("got type=%q, want=AttributeError", perr.Type)
+	}
+	if !strings.Contains(perr.Message, "schema") {
+		t.Fatalf("got message=%q, want it to mention schema", perr.Message)
+	}
+	if !strings.HasPrefix(perr.Traceback, "AttributeError") && !strings.HasPrefix(perr.Traceback, "Traceback") {
+		t.Fatalf("got traceback=%q", perr.Traceback)
+	}
+}
+
+func TestWithPath(t *testing.T) {
+	cause := &PythonError{Op: "could not get pyBuf"}
+	err := withPath(withPath(withPath(cause, "buffer %d", 1), "chunk %d", 3), "column %d %q", 2, "price")
+
+	want := `column 2 "price": chunk 3: buffer 1: could not get pyBuf`
+	if err.Error() != want {
+		t.Fatalf("got=%q, want=%q", err, want)
+	}
+	if got := err.(*ConversionError).Unwrap(); got != cause {
+		t.Fatalf("got cause=%v, want=%v", got, cause)
+	}
+}
@@ -1,52 +1,91 @@
 package bridge
 
 import (
-	"errors"
-
 	"github.com/DataDog/go-python3"
 	"github.com/apache/arrow/go/arrow"
 )
 
 // PyFieldToField given a Python field gets the Go Arrow field.
 func PyFieldToField(pyField *python3.PyObject) (*arrow.Field, error) {
-	pyName := pyField.GetAttrString("name")
+	checkGIL()
+
+	return pyFieldToField(pyField)
+}
+
+func pyFieldToField(pyField *python3.PyObject) (*arrow.Field, error) {
+	pyName := pyAttr(pyField, "name")
 	if pyName == nil {
-		return nil, errors.New("could not get pyName")
+		return nil, pyError("could not get pyName")
 	}
 	defer pyName.DecRef()
 
-	pyDtype := pyField.GetAttrString("type")
+	pyDtype := pyAttr(pyField, "type")
 	if pyDtype == nil {
-		return nil, errors.New("could not get pyDtype")
+		return nil, pyError("could not get pyDtype")
 	}
 	defer pyDtype.DecRef()
 
-	pyNullable := pyField.GetAttrString("nullable")
+	pyNullable := pyAttr(pyField, "nullable")
 	if pyNullable == nil {
-		return nil, errors.New("could not get pyNullable")
+		return nil, pyError("could not get pyNullable")
 	}
 	defer pyNullable.DecRef()
 
-	// TODO: Implement
-	// pyMetadata := CallPyFunc(pyField, "metadata")
-	// if pyMetadata == nil {
-	// 	return nil, errors.New("could not get pyMetadata")
-	// }
+	pyMetadata := pyAttr(pyField, "metadata")
+	if pyMetadata == nil {
+		return nil, pyError("could not get pyMetadata")
+	}
+	defer pyMetadata.DecRef()
 
 	name := python3.PyUnicode_AsUTF8(pyName)
-	dtype, err := PyDataTypeToDataType(pyDtype)
+	dtype, err := pyDataTypeToDataType(pyDtype)
+	if err != nil {
+		return nil, err
+	}
+	nullable := pyNullable.IsTrue() != 0
+	metadata, err := pyMetadataToMetadata(pyMetadata)
 	if err != nil {
 		return nil, err
 	}
-	nullable := python3.PyBool_Check(pyNullable)
 
 	field := &arrow.Field{
 		Name:     name,
 		Type:     dtype,
 		Nullable: nullable,
-		// TODO: Implement
-		// Metadata: metadata,
+		Metadata: metadata,
 	}
 
 	return field, nil
 }
+
+// FieldToPyField given a Go Arrow field gets the Python field.
+func FieldToPyField(field arrow.Field) (*python3.PyObject, error) {
+	checkGIL()
+
+	return fieldToPyField(field)
+}
+
+func fieldToPyField(field arrow.Field) (*python3.PyObject, error) {
+	pyDtype, err := dataTypeToPyDataType(field.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer pyDtype.DecRef()
+
+	pyName := python3.PyUnicode_FromString(field.Name)
+	defer pyName.DecRef()
+
+	pyNullable := python3.PyBool_FromLong(0)
+	if field.Nullable {
+		pyNullable = python3.PyBool_FromLong(1)
+	}
+	defer pyNullable.DecRef()
+
+	pyMetadata, err := metadataToPyMetadata(field.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	defer pyMetadata.DecRef()
+
+	return callPyArrowFunc("field", pyName, pyDtype, pyNullable, pyMetadata)
+}
@@ -0,0 +1,55 @@
+package bridge
+
+import (
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// foreignAllocator hands out a single, already allocated, region of memory
+// that is owned outside of Go. Free calls release instead of freeing the
+// memory. It allows memory.Buffer reference counting to manage the
+// lifetime of memory owned by C or Python.
+type foreignAllocator struct {
+	buf     []byte
+	release func()
+
+	accounting memory.Allocator
+	accounted  []byte
+}
+
+func (a *foreignAllocator) Allocate(size int) []byte {
+	if a.accounting != nil && a.accounted == nil {
+		a.accounted = a.accounting.Allocate(len(a.buf))
+	}
+	return a.buf
+}
+
+func (a *foreignAllocator) Reallocate(size int, b []byte) []byte {
+	panic("go-py-arrow-bridge: foreign buffers can not be reallocated")
+}
+
+func (a *foreignAllocator) Free(b []byte) {
+	if a.release != nil {
+		a.release()
+		a.release = nil
+	}
+	if a.accounted != nil {
+		a.accounting.Free(a.accounted)
+		a.accounted = nil
+	}
+	a.buf = nil
+}
+
+// newForeignBuffer returns a buffer viewing b. release is called once the
+// buffer has been released by all of its owners. accounting, when not nil,
+// is told about the buffer: an allocation of its size when it is created
+// and the matching free when it is released, see withForeignAccounting.
+// The returned buffer must be Release()'d after use.
+func newForeignBuffer(b []byte, release func(), accounting memory.Allocator) *memory.Buffer {
+	buffer := memory.NewResizableBuffer(&foreignAllocator{
+		buf:        b,
+		release:    release,
+		accounting: accounting,
+	})
+	buffer.Resize(len(b))
+	return buffer
+}
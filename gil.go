@@ -0,0 +1,34 @@
+package bridge
+
+import (
+	"runtime"
+
+	"github.com/DataDog/go-python3"
+)
+
+// withGIL runs fn while holding the GIL. The GIL state belongs to an OS
+// thread so the goroutine is locked to its thread until fn returns.
+// It is safe to call when the GIL is already held by the calling thread.
+func withGIL(fn func()) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	state := python3.PyGILState_Ensure()
+	defer python3.PyGILState_Release(state)
+
+	fn()
+}
+
+// checkGIL panics if the calling thread does not hold the GIL. Calling into
+// Python without it corrupts the interpreter, so the exported functions
+// check it rather than failing in obscure ways later. They call it once,
+// the unexported functions they call do not check again.
+//
+// It also resolves the Python cache of the interpreter for the conversion,
+// see getPyCache.
+func checkGIL() {
+	if !python3.PyGILState_Check() {
+		panic("go-py-arrow-bridge: the calling thread does not hold the GIL, use a Bridge or a pytasks task")
+	}
+	getPyCache()
+}
@@ -0,0 +1,167 @@
+package bridge
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// LazyTable is an array.Table over a pyarrow Table that converts a column
+// the first time it is requested, and keeps it. Only the schema is
+// converted up front.
+//
+// NewLazyTable must be called with the GIL held. The other methods acquire
+// the GIL themselves when they need it and can be called from any goroutine.
+// The pyarrow Table is referenced until the LazyTable is released.
+type LazyTable struct {
+	refCount int64
+
+	conv    *Converter
+	pyTable *python3.PyObject
+	schema  *arrow.Schema
+	indices []int // of the columns of schema in pyTable
+	numRows int64
+
+	mu   sync.Mutex
+	cols []*array.Column
+}
+
+// NewLazyTable returns a LazyTable over pyTable. It must be Release()'d
+// after use.
+func NewLazyTable(pyTable *python3.PyObject) (*LazyTable, error) {
+	return defaultConverter.NewLazyTable(pyTable)
+}
+
+// NewLazyTable is like the package level function, the columns are
+// converted with the options of c.
+func (c *Converter) NewLazyTable(pyTable *python3.PyObject) (*LazyTable, error) {
+	checkGIL()
+
+	if c.dictionaryMode != DictionaryKeep {
+		pyModeTable, err := pyTableWithDictionaryMode(pyTable, c.dictionaryMode)
+		if err != nil {
+			return nil, err
+		}
+		defer pyModeTable.DecRef()
+		pyTable = pyModeTable
+	}
+
+	pySchema, err := pySchemaFromPyTable(pyTable)
+	if err != nil {
+		return nil, err
+	}
+	defer pySchema.DecRef()
+
+	schema, indices, err := c.pySchemaToSchema(pySchema)
+	if err != nil {
+		return nil, err
+	}
+
+	numRows, ok := GetIntAttr(pyTable, "num_rows")
+	if !ok {
+		return nil, pyError("could not get num_rows")
+	}
+	lo, hi := c.rowRange(0, numRows)
+
+	pyTable.IncRef()
+	return &LazyTable{
+		refCount: 1,
+		conv:     c,
+		pyTable:  pyTable,
+		schema:   schema,
+		indices:  indices,
+		numRows:  int64(hi - lo),
+		cols:     make([]*array.Column, len(indices)),
+	}, nil
+}
+
+// Schema returns the schema of the table.
+func (t *LazyTable) Schema() *arrow.Schema { return t.schema }
+
+// NumRows returns the number of rows of the table.
+func (t *LazyTable) NumRows() int64 { return t.numRows }
+
+// NumCols returns the number of columns of the table.
+func (t *LazyTable) NumCols() int64 { return int64(len(t.cols)) }
+
+// Column returns the column i, converting it if this is the first time it
+// is requested. array.Table has no way to report an error, so Column panics
+// if the conversion fails; use LoadColumn to handle it.
+func (t *LazyTable) Column(i int) *array.Column {
+	col, err := t.LoadColumn(i)
+	if err != nil {
+		panic(err)
+	}
+	return col
+}
+
+// LoadColumn is Column returning the conversion error instead of panicking.
+// A failed conversion is retried by the next call.
+func (t *LazyTable) LoadColumn(i int) (*array.Column, error) {
+	t.mu.Lock()
+	col := t.cols[i]
+	t.mu.Unlock()
+	if col != nil {
+		return col, nil
+	}
+
+	// The lock is not held while waiting for the GIL, the goroutine holding
+	// the GIL may be waiting for the lock.
+	var err error
+	withGIL(func() {
+		col, err = t.convertColumn(i)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cols[i] != nil {
+		// Another goroutine converted the column meanwhile.
+		col.Release()
+		return t.cols[i], nil
+	}
+	t.cols[i] = col
+	return col, nil
+}
+
+func (t *LazyTable) convertColumn(i int) (*array.Column, error) {
+	field := t.schema.Field(i)
+	col, err := t.conv.pyTableGetColumn(t.pyTable, t.indices[i], field)
+	if err != nil {
+		return nil, withPath(err, "column %d %q", t.indices[i], field.Name)
+	}
+	return col, nil
+}
+
+// Retain increases the reference count by 1.
+// Retain may be called simultaneously from multiple goroutines.
+func (t *LazyTable) Retain() {
+	atomic.AddInt64(&t.refCount, 1)
+}
+
+// Release decreases the reference count by 1.
+// When the reference count goes to zero, the converted columns and the
+// pyarrow Table are released.
+func (t *LazyTable) Release() {
+	if atomic.AddInt64(&t.refCount, -1) == 0 {
+		for i, col := range t.cols {
+			if col != nil {
+				col.Release()
+				t.cols[i] = nil
+			}
+		}
+		withGIL(func() {
+			t.pyTable.DecRef()
+		})
+		t.pyTable = nil
+	}
+}
+
+var (
+	_ array.Table = (*LazyTable)(nil)
+)
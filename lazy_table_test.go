@@ -0,0 +1,80 @@
+package bridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+func TestLazyTable(t *testing.T) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	// The columns are deep copied so mem tells which ones were converted.
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	conv, err := NewConverter(WithCopyMode(DeepCopy), WithAllocator(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pyTable *python3.PyObject
+	var table *LazyTable
+	var refCount int
+	runPython(t, func() (err error) {
+		pyTable = CallPyFunc(fooModule, "zero_copy_chunks")
+		if pyTable == nil {
+			return pyError("could not call foo.zero_copy_chunks")
+		}
+		if refCount, err = pyRefCount(fooModule, pyTable); err != nil {
+			return err
+		}
+		table, err = conv.NewLazyTable(pyTable)
+		return err
+	})
+	defer runPython(t, func() error {
+		defer pyTable.DecRef()
+		// The LazyTable let go of its reference to pyTable.
+		got, err := pyRefCount(fooModule, pyTable)
+		if err != nil {
+			return err
+		}
+		if got != refCount {
+			return fmt.Errorf("got refcount=%d, want=%d", got, refCount)
+		}
+		return nil
+	})
+
+	// The schema is known without converting any column.
+	fields := table.Schema().Fields()
+	if got, want := fmt.Sprintf("%s: %v", fields[1].Name, fields[1].Type), "f1: utf8"; got != want {
+		t.Fatalf("got field=%s, want=%s", got, want)
+	}
+	if got, want := table.NumRows(), int64(20); got != want {
+		t.Fatalf("got=%d rows, want=%d", got, want)
+	}
+	mem.AssertSize(t, 0)
+
+	// Column acquires the GIL itself. The 5 chunks of the bool column each
+	// have a validity and a values bitmap of 64 bytes.
+	col := table.Column(2)
+	mem.AssertSize(t, 5*2*64)
+	if got := columnStrings(col); got[0] != "[true (null) false true]" {
+		t.Fatalf("got=%s, want=[true (null) false true]", got[0])
+	}
+	if table.Column(2) != col {
+		t.Fatal("the column was converted again")
+	}
+	mem.AssertSize(t, 5*2*64)
+
+	eager := pyTableFromFoo(t, "zero_copy_chunks")
+	defer eager.Release()
+	for i := 0; i < int(eager.NumCols()); i++ {
+		testColumn(t, table, i, fmt.Sprintf("%v", eager.Column(i).DataType()), columnStrings(eager.Column(i)))
+	}
+
+	table.Release()
+	mem.AssertSize(t, 0)
+}
@@ -0,0 +1,43 @@
+package bridge
+
+import (
+	"os"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestMain(m *testing.M) {
+	// Init Python
+	_ = pytasks.GetPythonSingleton()
+
+	code := m.Run()
+
+	// At this point we know we won't need Python anymore in this
+	// program, we can restore the state and lock the GIL to perform
+	// the final operations before exiting.
+	err := pytasks.GetPythonSingleton().Finalize()
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(code)
+}
+
+// importFoo imports the foo test module. The returned func releases it.
+func importFoo(tb testing.TB) (*python3.PyObject, func()) {
+	py := pytasks.GetPythonSingleton()
+	fooModule, err := py.ImportModule("foo")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return fooModule, func() {
+		err := py.NewTaskSync(func() {
+			fooModule.DecRef()
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package bridge
+
+// #cgo pkg-config: python3
+// #include <stdlib.h>
+// #include "bridge.h"
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+)
+
+// PyMetadataToMetadata converts the metadata of a pyarrow field or schema,
+// a dict of bytes keys to bytes values or None, to Go Arrow metadata.
+func PyMetadataToMetadata(pyMetadata *python3.PyObject) (arrow.Metadata, error) {
+	checkGIL()
+
+	return pyMetadataToMetadata(pyMetadata)
+}
+
+func pyMetadataToMetadata(pyMetadata *python3.PyObject) (arrow.Metadata, error) {
+	if pyMetadata == python3.Py_None {
+		return arrow.Metadata{}, nil
+	}
+	if !python3.PyDict_Check(pyMetadata) {
+		return arrow.Metadata{}, errors.New("metadata is not a dict")
+	}
+
+	pyItems := python3.PyDict_Items(pyMetadata)
+	if pyItems == nil {
+		return arrow.Metadata{}, pyError("could not get metadata items")
+	}
+	defer pyItems.DecRef()
+
+	length := python3.PyList_Size(pyItems)
+	keys := make([]string, 0, length)
+	values := make([]string, 0, length)
+	for i := 0; i < length; i++ {
+		pyItem := python3.PyList_GetItem(pyItems, i)
+		if pyItem == nil {
+			return arrow.Metadata{}, pyError("could not get metadata item")
+		}
+
+		key, err := pyBytesOrStrToString(python3.PyTuple_GetItem(pyItem, 0))
+		if err != nil {
+			return arrow.Metadata{}, err
+		}
+		value, err := pyBytesOrStrToString(python3.PyTuple_GetItem(pyItem, 1))
+		if err != nil {
+			return arrow.Metadata{}, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	return arrow.NewMetadata(keys, values), nil
+}
+
+// MetadataToPyMetadata converts Go Arrow metadata to a dict of bytes keys
+// to bytes values, or None if there is no metadata.
+func MetadataToPyMetadata(md arrow.Metadata) (*python3.PyObject, error) {
+	checkGIL()
+
+	return metadataToPyMetadata(md)
+}
+
+func metadataToPyMetadata(md arrow.Metadata) (*python3.PyObject, error) {
+	if md.Len() == 0 {
+		python3.Py_None.IncRef()
+		return python3.Py_None, nil
+	}
+
+	pyMetadata := python3.PyDict_New()
+	keys, values := md.Keys(), md.Values()
+	for i := range keys {
+		pyKey := newPyBytes(keys[i])
+		pyValue := newPyBytes(values[i])
+		ret := python3.PyDict_SetItem(pyMetadata, pyKey, pyValue)
+		pyKey.DecRef()
+		pyValue.DecRef()
+		if ret != 0 {
+			pyMetadata.DecRef()
+			return nil, pyError("could not set metadata item")
+		}
+	}
+	return pyMetadata, nil
+}
+
+// pyBytesOrStrToString returns the contents of a bytes or str object. Bytes
+// are copied as is, they may hold NUL bytes.
+func pyBytesOrStrToString(obj *python3.PyObject) (string, error) {
+	switch {
+	case obj == nil:
+		return "", pyError("could not get metadata key or value")
+	case python3.PyBytes_Check(obj):
+		cObj := (*C.PyObject)(unsafe.Pointer(obj))
+		return C.GoStringN(C.PyBytes_AsString(cObj), C.int(C.PyBytes_Size(cObj))), nil
+	case python3.PyUnicode_Check(obj):
+		return python3.PyUnicode_AsUTF8(obj), nil
+	}
+	return "", errors.New("metadata key or value is not bytes or str")
+}
+
+// newPyBytes returns a new bytes object holding a copy of s.
+func newPyBytes(s string) *python3.PyObject {
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	return (*python3.PyObject)(unsafe.Pointer(C.PyBytes_FromStringAndSize(cs, C.Py_ssize_t(len(s)))))
+}
@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+)
+
+func testMetadataValue(t *testing.T, md arrow.Metadata, key, want string) {
+	t.Helper()
+
+	i := md.FindKey(key)
+	if i < 0 {
+		t.Fatalf("metadata key %q is missing", key)
+	}
+	if want == "" {
+		return
+	}
+	if got := md.Values()[i]; got != want {
+		t.Fatalf("metadata key %q: got=%q, want=%q", key, got, want)
+	}
+}
+
+func testTableMetadata(t *testing.T, schema *arrow.Schema) {
+	t.Helper()
+
+	testMetadataValue(t, schema.Metadata(), "pandas", "")
+	testMetadataValue(t, schema.Metadata(), "origin", "test")
+
+	field := schema.Field(0)
+	if !field.HasMetadata() {
+		t.Fatalf("field %q has no metadata", field.Name)
+	}
+	testMetadataValue(t, field.Metadata, "lineage", "source-a")
+}
+
+func TestMetadata(t *testing.T) {
+	table := pyTableFromFoo(t, "metadata_table")
+	defer table.Release()
+
+	testTableMetadata(t, table.Schema())
+
+	t.Run("round trip", func(t *testing.T) {
+		got := pyTableRoundTrip(t, table)
+		defer got.Release()
+
+		testTableMetadata(t, got.Schema())
+	})
+}
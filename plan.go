@@ -0,0 +1,115 @@
+package bridge
+
+// #include "bridge.h"
+import "C"
+
+import (
+	"container/list"
+	"sync"
+	"unsafe"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+)
+
+// defaultPlanCacheSize is how many schemas a Converter remembers by default.
+const defaultPlanCacheSize = 64
+
+// conversionPlan is what converting the fields of a pyarrow schema
+// resolves: the Go fields, with their types, and the index in the pyarrow
+// schema of every field that was kept. Plans are shared by the conversions
+// and must not be modified.
+type conversionPlan struct {
+	fields  []arrow.Field
+	indices []int
+}
+
+// planCache maps the fingerprints of pyarrow schemas to their conversion
+// plans, so tables of a schema that was already seen skip the conversion of
+// its fields. The least recently used plan is evicted when the cache is
+// full. It is safe for concurrent use.
+type planCache struct {
+	mu    sync.Mutex
+	size  int
+	lru   *list.List // of *planEntry, most recently used first
+	plans map[string]*list.Element
+}
+
+type planEntry struct {
+	key  string
+	plan *conversionPlan
+}
+
+func newPlanCache(size int) *planCache {
+	return &planCache{
+		size:  size,
+		lru:   list.New(),
+		plans: make(map[string]*list.Element, size),
+	}
+}
+
+func (pc *planCache) get(key string) *conversionPlan {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	e, ok := pc.plans[key]
+	if !ok {
+		return nil
+	}
+	pc.lru.MoveToFront(e)
+	return e.Value.(*planEntry).plan
+}
+
+func (pc *planCache) put(key string, plan *conversionPlan) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if e, ok := pc.plans[key]; ok {
+		// Another goroutine converted the same schema meanwhile.
+		pc.lru.MoveToFront(e)
+		return
+	}
+	if pc.lru.Len() >= pc.size {
+		oldest := pc.lru.Back()
+		pc.lru.Remove(oldest)
+		delete(pc.plans, oldest.Value.(*planEntry).key)
+	}
+	pc.plans[key] = pc.lru.PushFront(&planEntry{key: key, plan: plan})
+}
+
+func (pc *planCache) len() int {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.lru.Len()
+}
+
+// pySchemaFingerprint identifies the fields of pySchema by the IPC
+// serialization of the schema without its own metadata, which covers the
+// names, types, nullability and metadata of the fields. The schema metadata
+// is left out as it often differs between tables of the same fields, e.g.
+// pandas records the range of the index of the DataFrame. It returns false,
+// with no exception set, if the schema can not be serialized.
+func pySchemaFingerprint(pySchema *python3.PyObject) (string, bool) {
+	pyFieldsSchema := CallPyFunc(pySchema, "remove_metadata")
+	if pyFieldsSchema == nil {
+		python3.PyErr_Clear()
+		return "", false
+	}
+	defer pyFieldsSchema.DecRef()
+
+	pyBuf := CallPyFunc(pyFieldsSchema, "serialize")
+	if pyBuf == nil {
+		python3.PyErr_Clear()
+		return "", false
+	}
+	defer pyBuf.DecRef()
+
+	pyBytes := CallPyFunc(pyBuf, "to_pybytes")
+	if pyBytes == nil || !python3.PyBytes_Check(pyBytes) {
+		pyBytes.DecRef()
+		python3.PyErr_Clear()
+		return "", false
+	}
+	defer pyBytes.DecRef()
+
+	cBytes := (*C.PyObject)(unsafe.Pointer(pyBytes))
+	return C.GoStringN(C.PyBytes_AsString(cBytes), C.int(C.PyBytes_Size(cBytes))), true
+}
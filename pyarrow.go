@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"github.com/DataDog/go-python3"
+)
+
+// importPyArrow returns a new reference to the pyarrow module.
+func importPyArrow() (*python3.PyObject, error) {
+	pyArrow := python3.PyImport_ImportModule("pyarrow")
+	if pyArrow == nil {
+		return nil, pyError("could not import pyarrow")
+	}
+	return pyArrow, nil
+}
+
+// getPyArrowAttr returns a new reference to the pyarrow module attribute name.
+func getPyArrowAttr(name string) (*python3.PyObject, error) {
+	if v := loadPyCache().pyArrow[name]; v != nil {
+		v.IncRef()
+		return v, nil
+	}
+
+	pyArrow, err := importPyArrow()
+	if err != nil {
+		return nil, err
+	}
+	defer pyArrow.DecRef()
+
+	v := pyArrow.GetAttrString(name)
+	if v == nil {
+		return nil, pyError("could not get pyarrow." + name)
+	}
+	return v, nil
+}
+
+// callPyArrowFunc imports pyarrow and calls the module level function name.
+func callPyArrowFunc(name string, args ...*python3.PyObject) (*python3.PyObject, error) {
+	fn, err := getPyArrowAttr(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.DecRef()
+
+	v := fn.CallFunctionObjArgs(args...)
+	if v == nil {
+		return nil, pyError("could not call pyarrow." + name)
+	}
+	return v, nil
+}
@@ -0,0 +1,178 @@
+package bridge
+
+// #include <stdlib.h>
+// #include "bridge.h"
+import "C"
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/DataDog/go-python3"
+)
+
+// pyAttrNames are the attribute and method names the conversions look up on
+// every chunk, field or column.
+var pyAttrNames = []string{
+	"__len__", "__next__", "_export_to_c", "buffers", "byte_width", "chunks",
+	"column", "data", "dictionary", "dictionary_decode", "dictionary_encode",
+	"field", "flatten", "from_arrays", "from_buffers", "id", "list_size",
+	"metadata", "name", "names", "null_count", "nullable", "num_children",
+	"num_columns", "num_rows", "offset", "ordered", "precision",
+	"remove_metadata", "scale", "schema", "serialize", "slice", "to_batches",
+	"to_pybytes", "type", "tz", "unify_dictionaries", "unit", "value_type",
+	"values",
+}
+
+// pyArrowAttrNames are the pyarrow functions and types the conversions call.
+// The type factories of pyDataTypeFactoryForType are cached too.
+var pyArrowAttrNames = []string{
+	"Array", "DictionaryArray", "RecordBatch", "Table", "binary",
+	"chunked_array", "decimal128", "dictionary", "duration", "field",
+	"foreign_buffer", "list_", "py_buffer", "schema", "struct", "time32",
+	"time64", "timestamp", "types",
+}
+
+// pyCache holds the Python objects the conversions use over and over, so
+// the hot loops do not allocate a Python string for every attribute they
+// get, nor import pyarrow for every function they call.
+//
+// The cache belongs to the interpreter that built it and is never modified,
+// its objects live as long as the interpreter. Py_Finalize drops it, see
+// goFinalizePyCache, as a new interpreter is likely to be allocated at the
+// same address.
+type pyCache struct {
+	interp unsafe.Pointer
+
+	names   map[string]*python3.PyObject // interned
+	pyArrow map[string]*python3.PyObject // nil if pyarrow could not be imported
+	pyTypes map[string]*python3.PyObject // the pyarrow.types predicates
+}
+
+var currentPyCache atomic.Value // *pyCache, nil after Py_Finalize
+
+// noPyCache is used until the cache is built: its nil maps make the callers
+// look everything up.
+var noPyCache = &pyCache{}
+
+// getPyCache returns the cache of the interpreter of the calling thread,
+// building it the first time. It must be called with the GIL held. It
+// crosses into C to identify the interpreter, so it is only called once per
+// conversion, by checkGIL; the lookups use loadPyCache.
+//
+// A sync.Once is not used: importing pyarrow may release the GIL, and a
+// goroutine holding the GIL could then wait on the Once for a goroutine
+// waiting on the GIL. Instead two goroutines may both build a cache, the
+// first one stored wins.
+func getPyCache() *pyCache {
+	interp := unsafe.Pointer(C.bridge_interpreter())
+	if c, _ := currentPyCache.Load().(*pyCache); c != nil && c.interp == interp {
+		return c
+	}
+
+	c := newPyCache(interp)
+	if cur, _ := currentPyCache.Load().(*pyCache); cur != nil && cur.interp == interp {
+		c.release()
+		return cur
+	}
+	if C.bridge_register_finalize() != 0 {
+		// Without the hook the cache could outlive the interpreter, keep an
+		// empty one: the callers then look everything up.
+		c.release()
+		c = &pyCache{interp: interp}
+	}
+	currentPyCache.Store(c)
+	return c
+}
+
+// loadPyCache returns the cache resolved by the last getPyCache, without
+// crossing into C. The cache is dropped when its interpreter is finalized,
+// so a cache it returns always belongs to the running interpreter.
+func loadPyCache() *pyCache {
+	if c, _ := currentPyCache.Load().(*pyCache); c != nil {
+		return c
+	}
+	return noPyCache
+}
+
+// goFinalizePyCache drops the cache when the interpreter is finalized. Its
+// objects are not released, they died with the interpreter.
+//
+//export goFinalizePyCache
+func goFinalizePyCache() {
+	currentPyCache.Store((*pyCache)(nil))
+}
+
+func newPyCache(interp unsafe.Pointer) *pyCache {
+	c := &pyCache{
+		interp: interp,
+		names:  make(map[string]*python3.PyObject, len(pyAttrNames)),
+	}
+	for _, name := range pyAttrNames {
+		cname := C.CString(name)
+		pyName := C.bridge_intern(cname)
+		C.free(unsafe.Pointer(cname))
+		if pyName == nil {
+			python3.PyErr_Clear()
+			continue
+		}
+		c.names[name] = (*python3.PyObject)(unsafe.Pointer(pyName))
+	}
+
+	// Without pyarrow the callers fall back to importing it, and report
+	// why that fails.
+	pyArrow := python3.PyImport_ImportModule("pyarrow")
+	if pyArrow == nil {
+		python3.PyErr_Clear()
+		return c
+	}
+	defer pyArrow.DecRef()
+
+	c.pyArrow = make(map[string]*python3.PyObject)
+	for _, name := range pyArrowAttrNames {
+		c.pyArrow[name] = getAttrOrNil(pyArrow, name)
+	}
+	for _, name := range pyDataTypeFactoryForType {
+		if name != "" {
+			c.pyArrow[name] = getAttrOrNil(pyArrow, name)
+		}
+	}
+
+	c.pyTypes = make(map[string]*python3.PyObject, len(pyUnsupportedTypePredicates)+len(pyTypePredicates))
+	if pyTypes := c.pyArrow["types"]; pyTypes != nil {
+		for _, name := range pyUnsupportedTypePredicates {
+			c.pyTypes[name] = getPyTypePredicate(pyTypes, name)
+		}
+		for _, p := range pyTypePredicates {
+			c.pyTypes[p.name] = getPyTypePredicate(pyTypes, p.name)
+		}
+	}
+	return c
+}
+
+// getAttrOrNil returns obj.name, or nil without an exception set if obj has
+// no such attribute, e.g. with older versions of pyarrow.
+func getAttrOrNil(obj *python3.PyObject, name string) *python3.PyObject {
+	v := obj.GetAttrString(name)
+	if v == nil {
+		python3.PyErr_Clear()
+	}
+	return v
+}
+
+func (c *pyCache) release() {
+	for _, m := range []map[string]*python3.PyObject{c.names, c.pyArrow, c.pyTypes} {
+		for _, v := range m {
+			v.DecRef()
+		}
+	}
+}
+
+// pyAttr returns a new reference to obj.name, looked up with the interned
+// name when there is one.
+func pyAttr(obj *python3.PyObject, name string) *python3.PyObject {
+	if pyName := loadPyCache().names[name]; pyName != nil {
+		return obj.GetAttr(pyName)
+	}
+	return obj.GetAttrString(name)
+}
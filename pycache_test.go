@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestPyCache(t *testing.T) {
+	err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		c := getPyCache()
+		if getPyCache() != c {
+			t.Error("the cache was built again")
+		}
+
+		for _, name := range pyAttrNames {
+			if c.names[name] == nil {
+				t.Errorf("name %q is not interned", name)
+			}
+		}
+		for _, name := range []string{"Array", "Table", "RecordBatch", "schema", "types", "int64"} {
+			if c.pyArrow[name] == nil {
+				t.Errorf("pyarrow.%s is not cached", name)
+			}
+		}
+		if c.pyTypes["is_struct"] == nil {
+			t.Error("pyarrow.types.is_struct is not cached")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPyCacheReinitialize checks that the cache does not outlive its
+// interpreter. pytasks can not be used after finalizing the interpreter, so
+// the test runs in a child process.
+func TestPyCacheReinitialize(t *testing.T) {
+	if os.Getenv("GO_PY_ARROW_BRIDGE_REINITIALIZE") == "1" {
+		pyCacheReinitialize()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestPyCacheReinitialize$")
+	cmd.Env = append(os.Environ(), "GO_PY_ARROW_BRIDGE_REINITIALIZE=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+}
+
+// pyCacheReinitialize builds the cache, finalizes the interpreter, builds
+// the cache of a new interpreter and exits.
+func pyCacheReinitialize() {
+	fail := func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+		os.Exit(1)
+	}
+	// numpy, and so pyarrow, can not be imported again after Py_Finalize,
+	// only the interned names are checked.
+	const noPyArrow = "import sys; sys.modules['pyarrow'] = None"
+
+	py := pytasks.GetPythonSingleton()
+	var first *pyCache
+	err := py.NewTaskSync(func() {
+		python3.PyRun_SimpleString(noPyArrow)
+		first = getPyCache()
+	})
+	if err != nil {
+		fail("%v", err)
+	}
+	if err := py.Finalize(); err != nil {
+		fail("%v", err)
+	}
+	if c, _ := currentPyCache.Load().(*pyCache); c != nil {
+		fail("the cache survived Py_Finalize")
+	}
+	if loadPyCache() != noPyCache {
+		fail("the lookups use the cache of the finalized interpreter")
+	}
+
+	runtime.LockOSThread()
+	python3.Py_Initialize()
+	python3.PyRun_SimpleString(noPyArrow)
+	c := getPyCache()
+	if c == first {
+		fail("got the cache of the finalized interpreter")
+	}
+	if got := python3.PyUnicode_AsUTF8(c.names["offset"]); got != "offset" {
+		fail("got interned name %q, want offset", got)
+	}
+	python3.Py_Finalize()
+
+	// TestMain would finalize pytasks again.
+	os.Exit(0)
+}
@@ -0,0 +1,151 @@
+package bridge
+
+import (
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// PyRecordBatchToRecord converts a pyarrow RecordBatch to a Go Record. The
+// returned record must be Release()'d after use.
+func PyRecordBatchToRecord(pyBatch *python3.PyObject) (array.Record, error) {
+	return defaultConverter.PyRecordBatchToRecord(pyBatch)
+}
+
+// PyRecordBatchToRecord is like the package level function with the options
+// of c.
+func (c *Converter) PyRecordBatchToRecord(pyBatch *python3.PyObject) (array.Record, error) {
+	checkGIL()
+
+	pySchema := pyAttr(pyBatch, "schema")
+	if pySchema == nil {
+		return nil, pyError("could not get pySchema")
+	}
+	defer pySchema.DecRef()
+
+	schema, indices, err := c.pySchemaToSchema(pySchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.rows {
+		numRows, ok := GetIntAttr(pyBatch, "num_rows")
+		if !ok {
+			return nil, pyError("could not get num_rows")
+		}
+		pyRows, err := c.pySliceRows(pyBatch, numRows)
+		if err != nil {
+			return nil, err
+		}
+		defer pyRows.DecRef()
+		pyBatch = pyRows
+	}
+
+	return c.pyRecordBatchToRecord(pyBatch, schema, indices)
+}
+
+// pyRecordBatchToRecord converts the columns at the given indices of a
+// pyarrow RecordBatch to a Go Record with the given schema. The Arrow C data
+// interface is used when pyarrow supports it and the whole batch is shared.
+func (c *Converter) pyRecordBatchToRecord(pyBatch *python3.PyObject, schema *arrow.Schema, indices []int) (array.Record, error) {
+	numCols, ok := GetIntAttr(pyBatch, "num_columns")
+	if !ok {
+		return nil, pyError("could not get num_columns")
+	}
+	if c.wholeBatch(indices, numCols) && pyBatch.HasAttrString("_export_to_c") {
+		return pyRecordBatchToRecordCData(pyBatch, schema)
+	}
+
+	numRows, ok := GetIntAttr(pyBatch, "num_rows")
+	if !ok {
+		return nil, pyError("could not get num_rows")
+	}
+
+	fields := schema.Fields()
+	cols := make([]array.Interface, 0, len(fields))
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+
+	for i := range fields {
+		pyIndex := python3.PyLong_FromLong(indices[i])
+		pyChunk := CallPyFunc(pyBatch, "column", pyIndex)
+		pyIndex.DecRef()
+		if pyChunk == nil {
+			return nil, pyError("could not get pyChunk from pyBatch")
+		}
+
+		chunk, err := c.pyChunkToChunk(pyChunk, fields[i].Type)
+		pyChunk.DecRef()
+		if err != nil {
+			return nil, withPath(err, "column %d %q", indices[i], fields[i].Name)
+		}
+		cols = append(cols, chunk)
+	}
+
+	return array.NewRecord(schema, cols, int64(numRows)), nil
+}
+
+// RecordToPyRecordBatch returns a pyarrow RecordBatch sharing the buffers of
+// the Go record. The Go buffers are retained until Python releases them.
+func RecordToPyRecordBatch(rec array.Record) (*python3.PyObject, error) {
+	checkGIL()
+
+	return recordToPyRecordBatch(rec)
+}
+
+func recordToPyRecordBatch(rec array.Record) (*python3.PyObject, error) {
+	schema := rec.Schema()
+	pySchema, err := schemaToPySchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	defer pySchema.DecRef()
+
+	fields := schema.Fields()
+	pyColumns := make([]*python3.PyObject, 0, len(fields))
+	defer func() {
+		for i := range pyColumns {
+			pyColumns[i].DecRef()
+		}
+	}()
+
+	for i := range fields {
+		pyColumn, err := columnToPyArray(rec.Column(i))
+		if err != nil {
+			return nil, withPath(err, "column %d %q", i, fields[i].Name)
+		}
+		pyColumns = append(pyColumns, pyColumn)
+	}
+
+	pyColumnList := NewPyList(pyColumns)
+	defer pyColumnList.DecRef()
+
+	pyRecordBatchType, err := getPyArrowAttr("RecordBatch")
+	if err != nil {
+		return nil, err
+	}
+	defer pyRecordBatchType.DecRef()
+
+	pyBatch := CallPyFuncKwargs(pyRecordBatchType, "from_arrays",
+		[]*python3.PyObject{pyColumnList},
+		map[string]*python3.PyObject{"schema": pySchema},
+	)
+	if pyBatch == nil {
+		return nil, pyError("could not call pyarrow.RecordBatch.from_arrays")
+	}
+	return pyBatch, nil
+}
+
+// columnToPyArray returns a pyarrow Array sharing the buffers of the Go array.
+func columnToPyArray(arr array.Interface) (*python3.PyObject, error) {
+	pyDtype, err := dataTypeToPyDataType(arr.DataType())
+	if err != nil {
+		return nil, err
+	}
+	defer pyDtype.DecRef()
+
+	return chunkToPyChunk(arr, pyDtype)
+}
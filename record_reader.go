@@ -0,0 +1,172 @@
+package bridge
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// PyRecordReader is an array.RecordReader that lazily pulls record batches
+// from a pyarrow RecordBatchReader, or any Python iterator of RecordBatches.
+//
+// NewPyRecordReader must be called with the GIL held. Next and Release
+// acquire the GIL themselves, and only for as long as it takes to fetch and
+// convert a single batch, so the reader can be consumed from any goroutine.
+type PyRecordReader struct {
+	refCount int64
+
+	pyIter    *python3.PyObject
+	pyPending *python3.PyObject // first batch, when it was read for its schema
+
+	conv    *Converter
+	schema  *arrow.Schema
+	indices []int // of the converted columns in the batches
+	cur     array.Record
+	done    bool
+	err     error
+}
+
+// NewPyRecordReader returns a reader over the record batches of pyReader.
+// The schema is taken from pyReader.schema when it exists, otherwise from
+// the first batch.
+func NewPyRecordReader(pyReader *python3.PyObject) (*PyRecordReader, error) {
+	return defaultConverter.NewPyRecordReader(pyReader)
+}
+
+// NewPyRecordReader is like the package level function, the batches are
+// converted with the options of c.
+func (c *Converter) NewPyRecordReader(pyReader *python3.PyObject) (*PyRecordReader, error) {
+	checkGIL()
+
+	pyIter := pyReader.GetIter()
+	if pyIter == nil {
+		return nil, pyError("could not get pyIter")
+	}
+
+	r := &PyRecordReader{refCount: 1, pyIter: pyIter, conv: c}
+
+	var pySchema *python3.PyObject
+	if pyReader.HasAttrString("schema") {
+		pySchema = pyAttr(pyReader, "schema")
+	} else {
+		pyBatch, err := nextPyItem(pyIter)
+		if err != nil {
+			pyIter.DecRef()
+			return nil, err
+		}
+		if pyBatch == nil {
+			pyIter.DecRef()
+			return nil, errors.New("can not determine the schema of an empty iterator")
+		}
+		r.pyPending = pyBatch
+		pySchema = pyAttr(pyBatch, "schema")
+	}
+	if pySchema == nil {
+		r.releasePy()
+		return nil, pyError("could not get pySchema")
+	}
+	defer pySchema.DecRef()
+
+	schema, indices, err := c.pySchemaToSchema(pySchema)
+	if err != nil {
+		r.releasePy()
+		return nil, err
+	}
+	r.schema = schema
+	r.indices = indices
+
+	return r, nil
+}
+
+// Retain increases the reference count by 1.
+// Retain may be called simultaneously from multiple goroutines.
+func (r *PyRecordReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+// Release decreases the reference count by 1.
+// When the reference count goes to zero, the current record and the Python
+// iterator are released.
+func (r *PyRecordReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		if r.cur != nil {
+			r.cur.Release()
+			r.cur = nil
+		}
+		withGIL(r.releasePy)
+	}
+}
+
+func (r *PyRecordReader) releasePy() {
+	if r.pyPending != nil {
+		r.pyPending.DecRef()
+		r.pyPending = nil
+	}
+	if r.pyIter != nil {
+		r.pyIter.DecRef()
+		r.pyIter = nil
+	}
+}
+
+// Schema returns the schema of the records.
+func (r *PyRecordReader) Schema() *arrow.Schema { return r.schema }
+
+// Record returns the current record. It is only valid until the next call
+// to Next.
+func (r *PyRecordReader) Record() array.Record { return r.cur }
+
+// Err returns the error, if any, that stopped the iteration.
+func (r *PyRecordReader) Err() error { return r.err }
+
+// Next pulls and converts the next batch from Python. It returns false when
+// the iterator is exhausted or an error occurred; see Err.
+func (r *PyRecordReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.done || r.err != nil {
+		return false
+	}
+
+	withGIL(func() {
+		pyBatch := r.pyPending
+		r.pyPending = nil
+		if pyBatch == nil {
+			pyBatch, r.err = nextPyItem(r.pyIter)
+			if r.err != nil {
+				return
+			}
+			if pyBatch == nil {
+				r.done = true
+				return
+			}
+		}
+		defer pyBatch.DecRef()
+
+		r.cur, r.err = r.conv.pyRecordBatchToRecord(pyBatch, r.schema, r.indices)
+	})
+
+	return r.cur != nil
+}
+
+// nextPyItem returns a new reference to the next item of pyIter, or nil
+// when the iterator is exhausted.
+func nextPyItem(pyIter *python3.PyObject) (*python3.PyObject, error) {
+	pyItem := CallPyFunc(pyIter, "__next__")
+	if pyItem == nil {
+		if python3.PyErr_ExceptionMatches(python3.PyExc_StopIteration) {
+			python3.PyErr_Clear()
+			return nil, nil
+		}
+		return nil, pyError("could not get next item from pyIter")
+	}
+	return pyItem, nil
+}
+
+var (
+	_ array.RecordReader = (*PyRecordReader)(nil)
+)
@@ -0,0 +1,74 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+func TestPyRecordReader(t *testing.T) {
+	for _, pyMethod := range []string{"record_batch_iter", "record_batch_stream"} {
+		t.Run(pyMethod, func(t *testing.T) {
+			testPyRecordReader(t, pyMethod)
+		})
+	}
+}
+
+func testPyRecordReader(t *testing.T, pyMethod string) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	numBatches := 5
+
+	var reader *PyRecordReader
+	var err error
+	taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyNumBatches := python3.PyLong_FromLong(numBatches)
+		defer pyNumBatches.DecRef()
+		pyReader := CallPyFunc(fooModule, pyMethod, pyNumBatches)
+		if pyReader == nil {
+			t.Error("pyReader is nil")
+			return
+		}
+		defer pyReader.DecRef()
+		reader, err = NewPyRecordReader(pyReader)
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reader == nil {
+		t.FailNow()
+	}
+	defer reader.Release()
+
+	if got, want := len(reader.Schema().Fields()), 3; got != want {
+		t.Fatalf("got=%d fields, want=%d", got, want)
+	}
+
+	// The reader acquires the GIL for each batch on its own.
+	batches := 0
+	for reader.Next() {
+		rec := reader.Record()
+		if got, want := rec.NumRows(), int64(4); got != want {
+			t.Fatalf("got=%d rows, want=%d", got, want)
+		}
+		f0 := rec.Column(0).(*array.Int64)
+		for i, want := range []int64{1, 2, 3, 4} {
+			if got := f0.Value(i); got != want {
+				t.Fatalf("batch %d: got=%d, want=%d", batches, got, want)
+			}
+		}
+		batches++
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if batches != numBatches {
+		t.Fatalf("got=%d batches, want=%d", batches, numBatches)
+	}
+}
@@ -0,0 +1,61 @@
+package bridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/nickpoorman/pytasks"
+)
+
+func testRecord(t *testing.T, rec array.Record) {
+	t.Helper()
+
+	if got, want := rec.NumRows(), int64(3); got != want {
+		t.Fatalf("got rows=%d, want=%d", got, want)
+	}
+	want := []string{"[1 (null) 3]", `["a" "b" (null)]`}
+	for i := range want {
+		if got := fmt.Sprintf("%v", rec.Column(i)); got != want[i] {
+			t.Fatalf("column %d: got=%s, want=%s", i, got, want[i])
+		}
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	var rec array.Record
+	withFooResult(t, "record_batch", func(pyBatch *python3.PyObject) (err error) {
+		rec, err = PyRecordBatchToRecord(pyBatch)
+		return err
+	})
+	defer rec.Release()
+
+	testRecord(t, rec)
+
+	t.Run("round trip", func(t *testing.T) {
+		var got array.Record
+		var err error
+		taskErr := pytasks.GetPythonSingleton().NewTaskSync(func() {
+			pyBatch, e := RecordToPyRecordBatch(rec)
+			if e != nil {
+				err = e
+				return
+			}
+			defer pyBatch.DecRef()
+			got, err = PyRecordBatchToRecord(pyBatch)
+		})
+		if taskErr != nil {
+			t.Fatal(taskErr)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer got.Release()
+
+		if !got.Schema().Equal(rec.Schema()) {
+			t.Fatalf("got schema=%v, want=%v", got.Schema(), rec.Schema())
+		}
+		testRecord(t, got)
+	})
+}
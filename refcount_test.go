@@ -0,0 +1,142 @@
+package bridge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+)
+
+// refCountIterations is how many times each conversion runs. A reference
+// leaked or over-released per conversion shows up as a drift of at least
+// this many references.
+const refCountIterations = 2000
+
+// refCountTolerance absorbs references the interpreter takes or drops on
+// its own while the conversions run, e.g. for None.
+const refCountTolerance = refCountIterations / 4
+
+// checkRefCountDrift calls fn refCountIterations times and checks that the
+// reference counts of objs did not drift.
+func checkRefCountDrift(fooModule *python3.PyObject, objs []*python3.PyObject, fn func() error) error {
+	before := make([]int, len(objs))
+	for i, obj := range objs {
+		n, err := pyRefCount(fooModule, obj)
+		if err != nil {
+			return err
+		}
+		before[i] = n
+	}
+
+	for i := 0; i < refCountIterations; i++ {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	for i, obj := range objs {
+		after, err := pyRefCount(fooModule, obj)
+		if err != nil {
+			return err
+		}
+		if drift := after - before[i]; drift > refCountTolerance || drift < -refCountTolerance {
+			return fmt.Errorf("object %d: refcount drifted by %d after %d conversions", i, drift, refCountIterations)
+		}
+	}
+	return nil
+}
+
+func goBuffersLen() int {
+	goBuffers.Lock()
+	defer goBuffers.Unlock()
+	return len(goBuffers.handles)
+}
+
+func TestRefCounts(t *testing.T) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	t.Run("PyChunkedToChunked", func(t *testing.T) {
+		withFooResult(t, "persistent_chunked", func(pyChunked *python3.PyObject) error {
+			pyChunks := pyChunked.GetAttrString("chunks")
+			if pyChunks == nil {
+				return pyError("could not get chunks")
+			}
+			defer pyChunks.DecRef()
+
+			objs := []*python3.PyObject{pyChunked, pyChunks}
+			for i := 0; i < python3.PyList_Size(pyChunks); i++ {
+				objs = append(objs, python3.PyList_GetItem(pyChunks, i))
+			}
+
+			if err := checkRefCountDrift(fooModule, objs, func() error {
+				chunked, err := PyChunkedToChunked(pyChunked, arrow.PrimitiveTypes.Int64)
+				if err != nil {
+					return err
+				}
+				chunked.Release()
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			// The chunks are borrowed from the list one by one when they
+			// are not all collected in one call.
+			for _, opt := range []ConverterOption{WithCopyMode(DeepCopy), WithRows(0, maxInt)} {
+				conv, err := NewConverter(opt)
+				if err != nil {
+					return err
+				}
+				if err := checkRefCountDrift(fooModule, objs, func() error {
+					chunked, err := conv.pyChunkedToChunked(pyChunked, arrow.PrimitiveTypes.Int64)
+					if err != nil {
+						return err
+					}
+					chunked.Release()
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+
+	t.Run("PyTableToTable", func(t *testing.T) {
+		withFooResult(t, "non_null_columns", func(pyTable *python3.PyObject) error {
+			objs := []*python3.PyObject{pyTable, python3.Py_None}
+			return checkRefCountDrift(fooModule, objs, func() error {
+				table, err := PyTableToTable(pyTable)
+				if err != nil {
+					return err
+				}
+				table.Release()
+				return nil
+			})
+		})
+	})
+
+	t.Run("TableToPyTable", func(t *testing.T) {
+		table := pyTableFromFoo(t, "non_null_columns")
+		defer table.Release()
+
+		before := goBuffersLen()
+		runPython(t, func() error {
+			objs := []*python3.PyObject{python3.Py_None}
+			return checkRefCountDrift(fooModule, objs, func() error {
+				pyTable, err := TableToPyTable(table)
+				if err != nil {
+					return err
+				}
+				pyTable.DecRef()
+				return nil
+			})
+		})
+
+		// Every Go buffer handed to Python has been released by its capsule.
+		if got := goBuffersLen(); got != before {
+			t.Fatalf("got %d Go buffers held by Python, want=%d", got, before)
+		}
+	})
+}
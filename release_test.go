@@ -0,0 +1,162 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// lockedAllocator serializes the calls to a memory.CheckedAllocator, which
+// is not safe for concurrent use.
+type lockedAllocator struct {
+	mu  sync.Mutex
+	mem *memory.CheckedAllocator
+}
+
+func (a *lockedAllocator) Allocate(size int) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mem.Allocate(size)
+}
+
+func (a *lockedAllocator) Reallocate(size int, b []byte) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mem.Reallocate(size, b)
+}
+
+func (a *lockedAllocator) Free(b []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.mem.Free(b)
+}
+
+// checkForeignBuffers returns an option accounting for every foreign buffer
+// the Converter creates, and a func failing the test if any of them was not
+// released.
+func checkForeignBuffers(t *testing.T) (ConverterOption, func()) {
+	t.Helper()
+
+	mem := &lockedAllocator{mem: memory.NewCheckedAllocator(memory.NewGoAllocator())}
+	return withForeignAccounting(mem), func() {
+		t.Helper()
+
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		mem.mem.AssertSize(t, 0)
+	}
+}
+
+// pyRefCount returns the Python reference count of obj as seen by foo.refcount.
+func pyRefCount(fooModule, obj *python3.PyObject) (int, error) {
+	pyN := CallPyFunc(fooModule, "refcount", obj)
+	if pyN == nil {
+		return 0, pyError("could not call foo.refcount")
+	}
+	defer pyN.DecRef()
+	return python3.PyLong_AsLong(pyN), nil
+}
+
+// checkRefCount calls fn and checks that it left the reference count of obj
+// unchanged.
+func checkRefCount(fooModule, obj *python3.PyObject, fn func() error) error {
+	before, err := pyRefCount(fooModule, obj)
+	if err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	after, err := pyRefCount(fooModule, obj)
+	if err != nil {
+		return err
+	}
+	if after != before {
+		return fmt.Errorf("got refcount=%d, want=%d", after, before)
+	}
+	return nil
+}
+
+func TestReleaseOnPartialFailure(t *testing.T) {
+	fooModule, release := importFoo(t)
+	defer release()
+
+	// The zero copy conversion of int64 chunks collects all the chunks in
+	// one call, the other options convert and release them one by one.
+	for _, tc := range []struct {
+		name string
+		opts func(mem memory.Allocator) []ConverterOption
+	}{
+		{"chunks", func(memory.Allocator) []ConverterOption { return nil }},
+		{"chunks deep copy", func(mem memory.Allocator) []ConverterOption {
+			return []ConverterOption{WithAllocator(mem), WithCopyMode(DeepCopy)}
+		}},
+		{"chunks rows", func(memory.Allocator) []ConverterOption {
+			return []ConverterOption{WithRows(0, maxInt)}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer pool.AssertSize(t, 0)
+			accounting, check := checkForeignBuffers(t)
+			defer check()
+			conv, err := NewConverter(append(tc.opts(pool), accounting)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			withFooResult(t, "broken_chunked", func(pyChunked *python3.PyObject) error {
+				return checkRefCount(fooModule, pyChunked, func() error {
+					// The first two chunks convert, the last one fails.
+					chunked, err := conv.pyChunkedToChunked(pyChunked, arrow.PrimitiveTypes.Int64)
+					if err == nil {
+						chunked.Release()
+						return errors.New("expected an error for a chunk that is not an array")
+					}
+					if want := "chunk 2: "; !strings.HasPrefix(err.Error(), want) {
+						return fmt.Errorf("got error=%q, want it to start with %q", err, want)
+					}
+					return nil
+				})
+			})
+		})
+	}
+
+	t.Run("columns", func(t *testing.T) {
+		accounting, check := checkForeignBuffers(t)
+		defer check()
+		conv, err := NewConverter(accounting)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The second column is a string column, converting it as a struct
+		// fails once the first column has been converted.
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "ints", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "strings", Type: arrow.StructOf(arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int64})},
+		}, nil)
+
+		withFooResult(t, "non_null_columns", func(pyTable *python3.PyObject) error {
+			return checkRefCount(fooModule, pyTable, func() error {
+				cols, err := conv.pyTableToColumnsAt(pyTable, schema, []int{0, 1})
+				if err == nil {
+					for i := range cols {
+						cols[i].Release()
+					}
+					return errors.New("expected an error for a string column converted as a struct")
+				}
+				if want := `column 1 "strings": `; !strings.HasPrefix(err.Error(), want) {
+					return fmt.Errorf("got error=%q, want it to start with %q", err, want)
+				}
+				return nil
+			})
+		})
+	})
+}
@@ -2,6 +2,7 @@ package bridge
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/DataDog/go-python3"
 	"github.com/apache/arrow/go/arrow"
@@ -9,15 +10,81 @@ import (
 
 // PySchemaFromPyTable returns a pyarrow schema from a pyarrow Table.
 func PySchemaFromPyTable(pyTable *python3.PyObject) (*python3.PyObject, error) {
-	pySchema := pyTable.GetAttrString("schema")
+	checkGIL()
+
+	return pySchemaFromPyTable(pyTable)
+}
+
+func pySchemaFromPyTable(pyTable *python3.PyObject) (*python3.PyObject, error) {
+	pySchema := pyAttr(pyTable, "schema")
 	if pySchema == nil {
-		return nil, errors.New("could not get pySchema")
+		return nil, pyError("could not get pySchema")
 	}
 	return pySchema, nil
 }
 
 // PySchemaToSchema given a Python schema gets the Go Arrow schema.
 func PySchemaToSchema(pySchema *python3.PyObject) (*arrow.Schema, error) {
+	return defaultConverter.PySchemaToSchema(pySchema)
+}
+
+// PySchemaToSchema is like the package level function, but only keeps the
+// columns selected by the options of c.
+func (c *Converter) PySchemaToSchema(pySchema *python3.PyObject) (*arrow.Schema, error) {
+	checkGIL()
+
+	schema, _, err := c.pySchemaToSchema(pySchema)
+	return schema, err
+}
+
+// pySchemaToSchema also returns the index in pySchema of every field of the
+// Go schema. The fields and indices come from the plan cache of c when the
+// fields of pySchema were already converted, they must not be modified.
+func (c *Converter) pySchemaToSchema(pySchema *python3.PyObject) (*arrow.Schema, []int, error) {
+	plan, err := c.schemaPlan(pySchema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pyMetadata := pyAttr(pySchema, "metadata")
+	if pyMetadata == nil {
+		return nil, nil, pyError("could not get pyMetadata")
+	}
+	defer pyMetadata.DecRef()
+
+	metadata, err := pyMetadataToMetadata(pyMetadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return arrow.NewSchema(plan.fields, &metadata), plan.indices, nil
+}
+
+// schemaPlan returns the conversion plan of the fields of pySchema, from the
+// plan cache of c when possible.
+func (c *Converter) schemaPlan(pySchema *python3.PyObject) (*conversionPlan, error) {
+	if c.plans == nil {
+		return c.compilePlan(pySchema)
+	}
+
+	key, ok := pySchemaFingerprint(pySchema)
+	if !ok {
+		return c.compilePlan(pySchema)
+	}
+	if plan := c.plans.get(key); plan != nil {
+		return plan, nil
+	}
+
+	plan, err := c.compilePlan(pySchema)
+	if err != nil {
+		return nil, err
+	}
+	c.plans.put(key, plan)
+	return plan, nil
+}
+
+// compilePlan converts the fields of pySchema selected by c.
+func (c *Converter) compilePlan(pySchema *python3.PyObject) (*conversionPlan, error) {
 	// start with the field names
 	pyFieldNames, err := getPyFieldNames(pySchema)
 	if err != nil {
@@ -29,19 +96,96 @@ func PySchemaToSchema(pySchema *python3.PyObject) (*arrow.Schema, error) {
 		}
 	}()
 
+	indices, err := c.selectColumns(pyFieldNames)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the fields
-	fields, err := getFields(pySchema, pyFieldNames)
+	fields, indices, err := c.getFields(pySchema, pyFieldNames, indices)
 	if err != nil {
 		return nil, err
 	}
 
-	return arrow.NewSchema(fields, nil), nil
+	if err := checkUniqueNames(fields, indices); err != nil {
+		return nil, err
+	}
+
+	return &conversionPlan{fields: fields, indices: indices}, nil
+}
+
+// checkUniqueNames returns a *DuplicateFieldError if two of the fields have
+// the same name, arrow.NewSchema and arrow.StructOf panic on them. indices
+// are the positions of the fields in the Python schema, nil if the fields
+// are all of them.
+func checkUniqueNames(fields []arrow.Field, indices []int) error {
+	seen := make(map[string]int, len(fields))
+	for i, field := range fields {
+		index := i
+		if indices != nil {
+			index = indices[i]
+		}
+		if first, dup := seen[field.Name]; dup {
+			return &DuplicateFieldError{Name: field.Name, Indices: [2]int{first, index}}
+		}
+		seen[field.Name] = index
+	}
+	return nil
+}
+
+// selectColumns returns the indices of the columns to convert.
+func (c *Converter) selectColumns(pyFieldNames []*python3.PyObject) ([]int, error) {
+	if c.columns == nil {
+		indices := make([]int, len(pyFieldNames))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices, nil
+	}
+
+	indices := make([]int, 0, len(c.columns))
+	for _, ref := range c.columns {
+		index := ref.index
+		if index < 0 {
+			index, err := columnIndex(pyFieldNames, ref.name)
+			if err != nil {
+				return nil, err
+			}
+			indices = append(indices, index)
+			continue
+		}
+		if index >= len(pyFieldNames) {
+			return nil, fmt.Errorf("%v not found", ref)
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// columnIndex returns the index of the column called name. Column names
+// need not be unique, a duplicated name is an error like it is in pyarrow;
+// such columns can only be selected by index.
+func columnIndex(pyFieldNames []*python3.PyObject, name string) (int, error) {
+	index := -1
+	for i, pyFieldName := range pyFieldNames {
+		if python3.PyUnicode_AsUTF8(pyFieldName) != name {
+			continue
+		}
+		if index >= 0 {
+			return -1, fmt.Errorf("column %q is not unique, select it by index", name)
+		}
+		index = i
+	}
+	if index < 0 {
+		return -1, fmt.Errorf("column %q not found", name)
+	}
+	return index, nil
 }
 
 func getPyFieldNames(pySchema *python3.PyObject) ([]*python3.PyObject, error) {
-	pyFieldNames := pySchema.GetAttrString("names")
+	pyFieldNames := pyAttr(pySchema, "names")
 	if pyFieldNames == nil {
-		return nil, errors.New("could not get pyFieldNames")
+		return nil, pyError("could not get pyFieldNames")
 	}
 	defer pyFieldNames.DecRef()
 
@@ -55,7 +199,7 @@ func getPyFieldNames(pySchema *python3.PyObject) ([]*python3.PyObject, error) {
 	for i := 0; i < length; i++ {
 		pyName := python3.PyList_GetItem(pyFieldNames, i)
 		if pyName == nil {
-			return nil, errors.New("could not get name")
+			return nil, pyError("could not get name")
 		}
 		pyName.IncRef()
 		// pyNames[i] = pyName
@@ -65,30 +209,79 @@ func getPyFieldNames(pySchema *python3.PyObject) ([]*python3.PyObject, error) {
 	return pyNames, nil
 }
 
-func getFields(pySchema *python3.PyObject, pyFieldNames []*python3.PyObject) ([]arrow.Field, error) {
-	fields := make([]arrow.Field, 0, len(pyFieldNames))
-	for _, pyFieldName := range pyFieldNames {
-		field, err := getField(pySchema, pyFieldName)
+// getFields converts the fields at the given indices. In LenientTypes mode
+// the fields of unsupported types are skipped; the indices of the fields
+// that were kept are returned.
+func (c *Converter) getFields(pySchema *python3.PyObject, pyFieldNames []*python3.PyObject, indices []int) ([]arrow.Field, []int, error) {
+	fields := make([]arrow.Field, 0, len(indices))
+	kept := make([]int, 0, len(indices))
+	for _, i := range indices {
+		field, err := getField(pySchema, i)
 		if err != nil {
-			return nil, err
+			if c.unknownTypes == LenientTypes && isUnsupportedType(err) {
+				continue
+			}
+			return nil, nil, withPath(err, "field %d %q", i, python3.PyUnicode_AsUTF8(pyFieldNames[i]))
 		}
 		// fields[i] = *field
 		fields = append(fields, *field)
+		kept = append(kept, i)
 	}
-	return fields, nil
+	return fields, kept, nil
 }
 
-func getField(schema *python3.PyObject, fieldName *python3.PyObject) (*arrow.Field, error) {
-	pyField := CallPyFunc(schema, "field_by_name", fieldName)
+// getField converts the field at index i of the schema. The field is looked
+// up by position, as names need not be unique.
+func getField(schema *python3.PyObject, i int) (*arrow.Field, error) {
+	pyIndex := python3.PyLong_FromLong(i)
+	defer pyIndex.DecRef()
+
+	pyField := schema.GetItem(pyIndex)
 	if pyField == nil {
-		return nil, errors.New("could not get pyField")
+		return nil, pyError("could not get pyField")
 	}
 	defer pyField.DecRef()
 
-	field, err := PyFieldToField(pyField)
+	field, err := pyFieldToField(pyField)
 	if err != nil {
 		return nil, err
 	}
 
 	return field, nil
 }
+
+// SchemaToPySchema given a Go Arrow schema gets the Python schema.
+func SchemaToPySchema(schema *arrow.Schema) (*python3.PyObject, error) {
+	checkGIL()
+
+	return schemaToPySchema(schema)
+}
+
+func schemaToPySchema(schema *arrow.Schema) (*python3.PyObject, error) {
+	fields := schema.Fields()
+	pyFields := make([]*python3.PyObject, 0, len(fields))
+	defer func() {
+		for i := range pyFields {
+			pyFields[i].DecRef()
+		}
+	}()
+
+	for i := range fields {
+		pyField, err := fieldToPyField(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		pyFields = append(pyFields, pyField)
+	}
+
+	pyFieldList := NewPyList(pyFields)
+	defer pyFieldList.DecRef()
+
+	pyMetadata, err := metadataToPyMetadata(schema.Metadata())
+	if err != nil {
+		return nil, err
+	}
+	defer pyMetadata.DecRef()
+
+	return callPyArrowFunc("schema", pyFieldList, pyMetadata)
+}
@@ -1,15 +1,29 @@
 package bridge
 
 import (
-	"errors"
-
 	"github.com/DataDog/go-python3"
 	"github.com/apache/arrow/go/arrow"
 	"github.com/apache/arrow/go/arrow/array"
 )
 
 func PyTableToTable(pyTable *python3.PyObject) (array.Table, error) {
-	schema, cols, err := PyTableToColumns(pyTable)
+	return defaultConverter.PyTableToTable(pyTable)
+}
+
+// PyTableToTable is like the package level function with the options of c.
+func (c *Converter) PyTableToTable(pyTable *python3.PyObject) (array.Table, error) {
+	checkGIL()
+
+	if c.dictionaryMode != DictionaryKeep {
+		pyModeTable, err := pyTableWithDictionaryMode(pyTable, c.dictionaryMode)
+		if err != nil {
+			return nil, err
+		}
+		defer pyModeTable.DecRef()
+		pyTable = pyModeTable
+	}
+
+	schema, cols, err := c.pyTableToColumns(pyTable)
 	if err != nil {
 		return nil, err
 	}
@@ -17,25 +31,36 @@ func PyTableToTable(pyTable *python3.PyObject) (array.Table, error) {
 	// Build the table
 	table := array.NewTable(schema, cols, -1) // -1 tells it to determine the numRows from the first column
 
+	// NewTable retains the columns it is given
+	for i := range cols {
+		cols[i].Release()
+	}
+
 	return table, nil
 }
 
 // PyTableToColumns returns the records in the pyarrow table.
 func PyTableToColumns(pyTable *python3.PyObject) (*arrow.Schema, []array.Column, error) {
+	checkGIL()
+
+	return defaultConverter.pyTableToColumns(pyTable)
+}
+
+func (c *Converter) pyTableToColumns(pyTable *python3.PyObject) (*arrow.Schema, []array.Column, error) {
 	// Get the PySchema from the PyTable
-	pySchema, err := PySchemaFromPyTable(pyTable)
+	pySchema, err := pySchemaFromPyTable(pyTable)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer pySchema.DecRef()
 
 	// Get the GoSchema
-	schema, err := PySchemaToSchema(pySchema)
+	schema, indices, err := c.pySchemaToSchema(pySchema)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	columns, err := PyTableToColumnsWithSchema(pyTable, schema)
+	columns, err := c.pyTableToColumnsAt(pyTable, schema, indices)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -43,21 +68,31 @@ func PyTableToColumns(pyTable *python3.PyObject) (*arrow.Schema, []array.Column,
 	return schema, columns, nil
 }
 
-// PyTableToColumns returns the columns in the pyarrow table.
+// PyTableToColumnsWithSchema returns the columns in the pyarrow table. The
+// field i of schema describes the column i of the table.
 func PyTableToColumnsWithSchema(pyTable *python3.PyObject, schema *arrow.Schema) ([]array.Column, error) {
+	checkGIL()
+
+	indices := make([]int, len(schema.Fields()))
+	for i := range indices {
+		indices[i] = i
+	}
+	return defaultConverter.pyTableToColumnsAt(pyTable, schema, indices)
+}
+
+// pyTableToColumnsAt returns the columns of the pyarrow table at the given
+// indices, described by the fields of schema.
+func (c *Converter) pyTableToColumnsAt(pyTable *python3.PyObject, schema *arrow.Schema, indices []int) ([]array.Column, error) {
 	fields := schema.Fields()
 	columns := make([]array.Column, 0, len(fields))
 
 	for i := range fields {
-		pyColumn, err := PyTableGetPyColumn(pyTable, fields[i].Name)
-		if err != nil {
-			return nil, err
-		}
-		defer pyColumn.DecRef()
-
-		col, err := PyColumnToColumnWithField(pyColumn, fields[i])
+		col, err := c.pyTableGetColumn(pyTable, indices[i], fields[i])
 		if err != nil {
-			return nil, err
+			for j := range columns {
+				columns[j].Release()
+			}
+			return nil, withPath(err, "column %d %q", indices[i], fields[i].Name)
 		}
 		// columns[i] = *col
 		columns = append(columns, *col)
@@ -66,15 +101,97 @@ func PyTableToColumnsWithSchema(pyTable *python3.PyObject, schema *arrow.Schema)
 	return columns, nil
 }
 
-// PyTableGetPyColumn returns the PyColumn given the name from the PyTable
+func (c *Converter) pyTableGetColumn(pyTable *python3.PyObject, i int, field arrow.Field) (*array.Column, error) {
+	pyColumn, err := pyTableGetPyColumnAt(pyTable, i)
+	if err != nil {
+		return nil, err
+	}
+	defer pyColumn.DecRef()
+
+	return c.pyColumnToColumnWithField(pyColumn, field)
+}
+
+// PyTableGetPyColumn returns the PyColumn given the name from the PyTable.
+// It fails if the name is not unique, see PyTableGetPyColumnAt.
 func PyTableGetPyColumn(pyTable *python3.PyObject, name string) (*python3.PyObject, error) {
+	checkGIL()
+
 	pyName := python3.PyUnicode_FromString(name)
 	defer pyName.DecRef()
 
 	pyColumn := CallPyFunc(pyTable, "column", pyName)
 	if pyColumn == nil {
-		return nil, errors.New("could not get pyColumn")
+		return nil, pyError("could not get pyColumn")
 	}
 
 	return pyColumn, nil
 }
+
+// PyTableGetPyColumnAt returns the PyColumn at index i of the PyTable.
+func PyTableGetPyColumnAt(pyTable *python3.PyObject, i int) (*python3.PyObject, error) {
+	checkGIL()
+
+	return pyTableGetPyColumnAt(pyTable, i)
+}
+
+func pyTableGetPyColumnAt(pyTable *python3.PyObject, i int) (*python3.PyObject, error) {
+	pyIndex := python3.PyLong_FromLong(i)
+	defer pyIndex.DecRef()
+
+	pyColumn := CallPyFunc(pyTable, "column", pyIndex)
+	if pyColumn == nil {
+		return nil, pyError("could not get pyColumn")
+	}
+
+	return pyColumn, nil
+}
+
+// TableToPyTable returns a pyarrow Table sharing the buffers of the Go table.
+// The Go buffers are retained until Python releases them.
+func TableToPyTable(table array.Table) (*python3.PyObject, error) {
+	checkGIL()
+
+	return tableToPyTable(table)
+}
+
+func tableToPyTable(table array.Table) (*python3.PyObject, error) {
+	pySchema, err := schemaToPySchema(table.Schema())
+	if err != nil {
+		return nil, err
+	}
+	defer pySchema.DecRef()
+
+	numCols := int(table.NumCols())
+	pyColumns := make([]*python3.PyObject, 0, numCols)
+	defer func() {
+		for i := range pyColumns {
+			pyColumns[i].DecRef()
+		}
+	}()
+
+	for i := 0; i < numCols; i++ {
+		pyColumn, err := columnToPyChunked(table.Column(i))
+		if err != nil {
+			return nil, withPath(err, "column %d %q", i, table.Column(i).Name())
+		}
+		pyColumns = append(pyColumns, pyColumn)
+	}
+
+	pyColumnList := NewPyList(pyColumns)
+	defer pyColumnList.DecRef()
+
+	pyTableType, err := getPyArrowAttr("Table")
+	if err != nil {
+		return nil, err
+	}
+	defer pyTableType.DecRef()
+
+	pyTable := CallPyFuncKwargs(pyTableType, "from_arrays",
+		[]*python3.PyObject{pyColumnList},
+		map[string]*python3.PyObject{"schema": pySchema},
+	)
+	if pyTable == nil {
+		return nil, pyError("could not call pyarrow.Table.from_arrays")
+	}
+	return pyTable, nil
+}
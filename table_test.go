@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/DataDog/go-python3"
+	"github.com/apache/arrow/go/arrow"
 	"github.com/apache/arrow/go/arrow/array"
 	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/go-bullseye/bullseye/dataframe"
@@ -13,31 +14,107 @@ import (
 
 func BenchmarkAll(b *testing.B) {
 	for i := 5; i <= 10; i += 2 {
-		b.Run(fmt.Sprintf("BenchmarkZeroCopyChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks"))
+		b.Run(fmt.Sprintf("BenchmarkZeroCopyChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks", PyTableToTable))
 	}
 	for i := 1000; i <= 10000; i += 500 {
-		b.Run(fmt.Sprintf("BenchmarkZeroCopyChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks"))
+		b.Run(fmt.Sprintf("BenchmarkZeroCopyChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks", PyTableToTable))
 	}
 	for i := 5; i <= 10; i += 2 {
-		b.Run(fmt.Sprintf("BenchmarkZeroCopyElements_%d", i), zeroCopyBenchmarkN(i, "zero_copy_elements"))
+		b.Run(fmt.Sprintf("BenchmarkPerChunkChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks", perChunkPyTableToTable))
 	}
 	for i := 1000; i <= 10000; i += 500 {
-		b.Run(fmt.Sprintf("BenchmarkZeroCopyElements_%d", i), zeroCopyBenchmarkN(i, "zero_copy_elements"))
+		b.Run(fmt.Sprintf("BenchmarkPerChunkChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks", perChunkPyTableToTable))
 	}
+	for i := 5; i <= 10; i += 2 {
+		b.Run(fmt.Sprintf("BenchmarkZeroCopyElements_%d", i), zeroCopyBenchmarkN(i, "zero_copy_elements", PyTableToTable))
+	}
+	for i := 1000; i <= 10000; i += 500 {
+		b.Run(fmt.Sprintf("BenchmarkZeroCopyElements_%d", i), zeroCopyBenchmarkN(i, "zero_copy_elements", PyTableToTable))
+	}
+	if cdataSupported() {
+		for i := 5; i <= 10; i += 2 {
+			b.Run(fmt.Sprintf("BenchmarkCDataChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks", PyTableToTableCData))
+		}
+		for i := 1000; i <= 10000; i += 500 {
+			b.Run(fmt.Sprintf("BenchmarkCDataChunks_%d", i), zeroCopyBenchmarkN(i, "zero_copy_chunks", PyTableToTableCData))
+		}
+	}
+}
+
+// perChunkPyTableToTable converts pyTable with several calls into Python
+// per chunk, the way PyTableToTable did before the chunks were collected in
+// a single cgo call. It is the baseline of BenchmarkZeroCopyChunks.
+func perChunkPyTableToTable(pyTable *python3.PyObject) (array.Table, error) {
+	pySchema, err := PySchemaFromPyTable(pyTable)
+	if err != nil {
+		return nil, err
+	}
+	defer pySchema.DecRef()
+
+	schema, err := PySchemaToSchema(pySchema)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := schema.Fields()
+	cols := make([]array.Column, 0, len(fields))
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+	for i := range fields {
+		chunks, err := perChunkPyColumn(pyTable, i, fields[i].Type)
+		if err != nil {
+			return nil, err
+		}
+		chunked := array.NewChunked(fields[i].Type, chunks)
+		for _, chunk := range chunks {
+			chunk.Release()
+		}
+		cols = append(cols, *array.NewColumn(fields[i], chunked))
+		chunked.Release()
+	}
+	return array.NewTable(schema, cols, -1), nil
+}
+
+func perChunkPyColumn(pyTable *python3.PyObject, i int, dtype arrow.DataType) ([]array.Interface, error) {
+	pyColumn, err := PyTableGetPyColumnAt(pyTable, i)
+	if err != nil {
+		return nil, err
+	}
+	defer pyColumn.DecRef()
 
-	// At this point we know we won't need Python anymore in this
-	// program, we can restore the state and lock the GIL to perform
-	// the final operations before exiting.
-	err := pytasks.GetPythonSingleton().Finalize()
+	pyChunked, err := PyColumnGetPyChunked(pyColumn)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	defer pyChunked.DecRef()
+
+	pyChunks, err := PyChunkedGetPyChunks(pyChunked)
+	if err != nil {
+		return nil, err
+	}
+	defer pyChunks.DecRef()
+
+	chunks := make([]array.Interface, 0, python3.PyList_Size(pyChunks))
+	for j := 0; j < python3.PyList_Size(pyChunks); j++ {
+		chunk, err := PyChunksGetChunk(pyChunks, j, dtype)
+		if err != nil {
+			for _, chunk := range chunks {
+				chunk.Release()
+			}
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
 }
 
 // So the benchmarks don't get compiled out during optimization.
 var benchTable array.Table
 
-func zeroCopyBenchmarkN(numChunks int, pyMethod string) func(b *testing.B) {
+func zeroCopyBenchmarkN(numChunks int, pyMethod string, convert func(*python3.PyObject) (array.Table, error)) func(b *testing.B) {
 	return func(b *testing.B) {
 		if numChunks <= 0 {
 			b.Fatal("numChunks must be greater than zero")
@@ -87,7 +164,7 @@ func zeroCopyBenchmarkN(numChunks int, pyMethod string) func(b *testing.B) {
 			// grabbing the GIL over and over. When the loop
 			// is outside the task, the results are still consistent.
 			for i := 0; i < b.N; i++ {
-				table, err = PyTableToTable(pyTable)
+				table, err = convert(pyTable)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -101,22 +178,97 @@ func zeroCopyBenchmarkN(numChunks int, pyMethod string) func(b *testing.B) {
 	}
 }
 
-func TestTable(t *testing.T) {
-	// Init Python
-	_ = pytasks.GetPythonSingleton()
+func TestBatchedChunks(t *testing.T) {
+	for _, method := range []string{"zero_copy_chunks", "primitive_types", "sliced_types"} {
+		t.Run(method, func(t *testing.T) {
+			var table, want array.Table
+			withFooResult(t, method, func(pyTable *python3.PyObject) (err error) {
+				if table, err = PyTableToTable(pyTable); err != nil {
+					return err
+				}
+				want, err = perChunkPyTableToTable(pyTable)
+				return err
+			})
+			defer table.Release()
+			defer want.Release()
+
+			for i := 0; i < int(want.NumCols()); i++ {
+				testColumn(t, table, i, fmt.Sprintf("%v", want.Column(i).DataType()), columnStrings(want.Column(i)))
+			}
+		})
+	}
+}
 
+func TestTable(t *testing.T) {
 	t.Run("PyTableToTable", testPyTableToTable)
+	t.Run("TableToPyTable", testTableToPyTable)
+	t.Run("PyTableToTableCData", testPyTableToTableCData)
+}
+
+func testPyTableToTable(t *testing.T) {
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	py := pytasks.GetPythonSingleton()
+	fooModule, err := py.ImportModule("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+			fooModule.DecRef()
+		})
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	var table array.Table
+	taskErr := py.NewTaskSync(func() {
+		pyTable := genPyTable(fooModule)
+		table, err = PyTableToTable(pyTable)
+		pyTable.DecRef()
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	// At this point we know we won't need Python anymore in this
-	// program, we can restore the state and lock the GIL to perform
-	// the final operations before exiting.
-	err := pytasks.GetPythonSingleton().Finalize()
+	// Wrapping it in a bullseye dataframe allows us to print it easily
+	df, err := dataframe.NewDataFrameFromTable(pool, table)
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	got := df.Display(0)
+	want := `rec[0]["f0"]: [1 2 3 4]
+rec[0]["f1"]: ["foo" "bar" "baz" (null)]
+rec[0]["f2"]: [true (null) false true]
+rec[1]["f0"]: [1 2 3 4]
+rec[1]["f1"]: ["foo" "bar" "baz" (null)]
+rec[1]["f2"]: [true (null) false true]
+rec[2]["f0"]: [1 2 3 4]
+rec[2]["f1"]: ["foo" "bar" "baz" (null)]
+rec[2]["f2"]: [true (null) false true]
+rec[3]["f0"]: [1 2 3 4]
+rec[3]["f1"]: ["foo" "bar" "baz" (null)]
+rec[3]["f2"]: [true (null) false true]
+rec[4]["f0"]: [1 2 3 4]
+rec[4]["f1"]: ["foo" "bar" "baz" (null)]
+rec[4]["f2"]: [true (null) false true]
+`
+	if got != want {
+		t.Fatalf("\ngot=\n%v\nwant=\n%v", got, want)
+	}
 }
 
-func testPyTableToTable(t *testing.T) {
+func testPyTableToTableCData(t *testing.T) {
+	if !cdataSupported() {
+		t.Skip("the installed pyarrow does not support the Arrow C data interface")
+	}
+
 	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
 	defer pool.AssertSize(t, 0)
 
@@ -137,7 +289,7 @@ func testPyTableToTable(t *testing.T) {
 	var table array.Table
 	taskErr := py.NewTaskSync(func() {
 		pyTable := genPyTable(fooModule)
-		table, err = PyTableToTable(pyTable)
+		table, err = PyTableToTableCData(pyTable)
 		pyTable.DecRef()
 	})
 	if taskErr != nil {
@@ -146,12 +298,13 @@ func testPyTableToTable(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer table.Release()
 
-	// Wrapping it in a bullseye dataframe allows us to print it easily
 	df, err := dataframe.NewDataFrameFromTable(pool, table)
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer df.Release()
 
 	got := df.Display(0)
 	want := `rec[0]["f0"]: [1 2 3 4]
@@ -175,6 +328,80 @@ rec[4]["f2"]: [true (null) false true]
 	}
 }
 
+// cdataSupported reports whether the installed pyarrow can export through
+// the Arrow C data interface.
+func cdataSupported() bool {
+	var supported bool
+	err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+		pyArrow, err := importPyArrow()
+		if err != nil {
+			return
+		}
+		defer pyArrow.DecRef()
+
+		pyArrayType := pyArrow.GetAttrString("Array")
+		if pyArrayType == nil {
+			return
+		}
+		defer pyArrayType.DecRef()
+		supported = pyArrayType.HasAttrString("_export_to_c")
+	})
+	return err == nil && supported
+}
+
+func testTableToPyTable(t *testing.T) {
+	py := pytasks.GetPythonSingleton()
+	fooModule, err := py.ImportModule("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := pytasks.GetPythonSingleton().NewTaskSync(func() {
+			fooModule.DecRef()
+		})
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	var equal bool
+	taskErr := py.NewTaskSync(func() {
+		pyTable := genPyTable(fooModule)
+		defer pyTable.DecRef()
+
+		var table array.Table
+		table, err = PyTableToTable(pyTable)
+		if err != nil {
+			return
+		}
+		defer table.Release()
+
+		var pyRoundTrip *python3.PyObject
+		pyRoundTrip, err = TableToPyTable(table)
+		if err != nil {
+			return
+		}
+		defer pyRoundTrip.DecRef()
+
+		pyEqual := CallPyFunc(pyRoundTrip, "equals", pyTable)
+		if pyEqual == nil {
+			t.Error("could not call equals")
+			return
+		}
+		defer pyEqual.DecRef()
+		equal = pyEqual.IsTrue() == 1
+	})
+	if taskErr != nil {
+		t.Fatal(taskErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal {
+		t.Fatal("pyarrow Table from Go is not equal to the original pyarrow Table")
+	}
+}
+
 func genPyTable(module *python3.PyObject) *python3.PyObject {
 	pyTable := CallPyFunc(module, "zero_copy_chunks")
 	if pyTable == nil {
@@ -182,3 +409,158 @@ func genPyTable(module *python3.PyObject) *python3.PyObject {
 	}
 	return pyTable
 }
+
+func TestCheckUniqueNames(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: "x", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "x", Type: arrow.PrimitiveTypes.Int64},
+	}
+	if err := checkUniqueNames(fields[:2], nil); err != nil {
+		t.Fatal(err)
+	}
+
+	err := checkUniqueNames(fields, []int{1, 3, 4})
+	want := &DuplicateFieldError{Name: "x", Indices: [2]int{1, 4}}
+	if derr, ok := err.(*DuplicateFieldError); !ok || *derr != *want {
+		t.Fatalf("got error=%v, want=%v", err, want)
+	}
+}
+
+func TestDuplicateColumnNames(t *testing.T) {
+	wantChunks := [][]string{{"[1 2]"}, {`["a" "b"]`}, {"[1.5 2.5]"}, {"[true false]"}}
+
+	// Go arrow schemas can not hold two fields of the same name.
+	testDuplicate := func(t *testing.T, err error, name string, indices [2]int) {
+		t.Helper()
+		if cerr, ok := err.(*ConversionError); ok {
+			err = cerr.Err
+		}
+		derr, ok := err.(*DuplicateFieldError)
+		if !ok {
+			t.Fatalf("got error=%v of type %T, want=*DuplicateFieldError", err, err)
+		}
+		if derr.Name != name || derr.Indices != indices {
+			t.Fatalf("got name=%q indices=%v, want name=%q indices=%v", derr.Name, derr.Indices, name, indices)
+		}
+	}
+	convert := func(t *testing.T, pyMethod string, fn func(pyObj *python3.PyObject) (func(), error)) error {
+		t.Helper()
+		var convErr error
+		withFooResult(t, pyMethod, func(pyObj *python3.PyObject) error {
+			release, err := fn(pyObj)
+			if err == nil {
+				release()
+			}
+			convErr = err
+			return nil
+		})
+		return convErr
+	}
+
+	t.Run("table", func(t *testing.T) {
+		err := convert(t, "duplicate_names", func(pyTable *python3.PyObject) (func(), error) {
+			table, err := PyTableToTable(pyTable)
+			if err != nil {
+				return nil, err
+			}
+			return table.Release, nil
+		})
+		testDuplicate(t, err, "x", [2]int{0, 1})
+	})
+
+	t.Run("record batch", func(t *testing.T) {
+		err := convert(t, "duplicate_names_batch", func(pyBatch *python3.PyObject) (func(), error) {
+			rec, err := PyRecordBatchToRecord(pyBatch)
+			if err != nil {
+				return nil, err
+			}
+			return rec.Release, nil
+		})
+		testDuplicate(t, err, "x", [2]int{0, 1})
+	})
+
+	t.Run("cdata", func(t *testing.T) {
+		if !cdataSupported() {
+			t.Skip("the installed pyarrow does not support the Arrow C data interface")
+		}
+		err := convert(t, "duplicate_names", func(pyTable *python3.PyObject) (func(), error) {
+			table, err := PyTableToTableCData(pyTable)
+			if err != nil {
+				return nil, err
+			}
+			return table.Release, nil
+		})
+		testDuplicate(t, err, "x", [2]int{0, 1})
+	})
+
+	t.Run("struct fields", func(t *testing.T) {
+		err := convert(t, "duplicate_struct_fields", func(pyTable *python3.PyObject) (func(), error) {
+			table, err := PyTableToTable(pyTable)
+			if err != nil {
+				return nil, err
+			}
+			return table.Release, nil
+		})
+		testDuplicate(t, err, "a", [2]int{0, 1})
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		conv, err := NewConverter(WithColumnIndices(0, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var table array.Table
+		withFooResult(t, "duplicate_names", func(pyTable *python3.PyObject) (err error) {
+			table, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer table.Release()
+
+		got := pyTableRoundTrip(t, table)
+		defer got.Release()
+		if names, want := fmt.Sprintf("%q", tableNames(got)), `["x" ""]`; names != want {
+			t.Fatalf("got columns=%s, want=%s", names, want)
+		}
+		testColumn(t, got, 0, "int64", wantChunks[0])
+		testColumn(t, got, 1, "float64", wantChunks[2])
+	})
+
+	t.Run("select by index", func(t *testing.T) {
+		conv, err := NewConverter(WithColumnIndices(1, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got array.Table
+		withFooResult(t, "duplicate_names", func(pyTable *python3.PyObject) (err error) {
+			got, err = conv.PyTableToTable(pyTable)
+			return err
+		})
+		defer got.Release()
+
+		testColumn(t, got, 0, "utf8", wantChunks[1])
+		testColumn(t, got, 1, "float64", wantChunks[2])
+	})
+
+	t.Run("select by name", func(t *testing.T) {
+		conv, err := NewConverter(WithColumns("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var convErr error
+		withFooResult(t, "duplicate_names", func(pyTable *python3.PyObject) error {
+			got, err := conv.PyTableToTable(pyTable)
+			if err == nil {
+				got.Release()
+			}
+			convErr = err
+			return nil
+		})
+		if want := `column "x" is not unique, select it by index`; convErr == nil || convErr.Error() != want {
+			t.Fatalf("got error=%v, want=%s", convErr, want)
+		}
+	})
+}
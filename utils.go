@@ -4,17 +4,72 @@ import "github.com/DataDog/go-python3"
 
 // A helper for first fetching the function and then calling it
 func CallPyFunc(obj *python3.PyObject, name string, args ...*python3.PyObject) *python3.PyObject {
+	if pyName := loadPyCache().names[name]; pyName != nil {
+		return obj.CallMethodObjArgs(pyName, args...)
+	}
+
 	fn := obj.GetAttrString(name)
+	if fn == nil {
+		return nil
+	}
 	defer fn.DecRef()
 
 	return fn.CallFunctionObjArgs(args...)
 }
 
 func GetIntAttr(obj *python3.PyObject, attr string) (int, bool) {
-	v := obj.GetAttrString(attr)
+	v := pyAttr(obj, attr)
 	if v == nil {
 		return 0, false
 	}
 	defer v.DecRef()
 	return python3.PyLong_AsLong(v), true
 }
+
+// CallPyFuncKwargs fetches the function name from obj and calls it with the
+// positional args and keyword arguments kwargs.
+func CallPyFuncKwargs(obj *python3.PyObject, name string, args []*python3.PyObject, kwargs map[string]*python3.PyObject) *python3.PyObject {
+	fn := pyAttr(obj, name)
+	if fn == nil {
+		return nil
+	}
+	defer fn.DecRef()
+
+	pyArgs := python3.PyTuple_New(len(args))
+	defer pyArgs.DecRef()
+	for i, arg := range args {
+		// PyTuple_SetItem steals the reference
+		arg.IncRef()
+		python3.PyTuple_SetItem(pyArgs, i, arg)
+	}
+
+	pyKwargs := python3.PyDict_New()
+	defer pyKwargs.DecRef()
+	for k, v := range kwargs {
+		python3.PyDict_SetItemString(pyKwargs, k, v)
+	}
+
+	return fn.Call(pyArgs, pyKwargs)
+}
+
+// NewPyList returns a new Python list holding new references to items.
+func NewPyList(items []*python3.PyObject) *python3.PyObject {
+	pyList := python3.PyList_New(len(items))
+	for i, item := range items {
+		// PyList_SetItem steals the reference
+		item.IncRef()
+		python3.PyList_SetItem(pyList, i, item)
+	}
+	return pyList
+}
+
+// PyObjectString returns str(obj), or "<unknown>" if it fails.
+func PyObjectString(obj *python3.PyObject) string {
+	pyStr := obj.Str()
+	if pyStr == nil {
+		python3.PyErr_Clear()
+		return "<unknown>"
+	}
+	defer pyStr.DecRef()
+	return python3.PyUnicode_AsUTF8(pyStr)
+}